@@ -0,0 +1,262 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"strings"
+	"time"
+
+	pb "github.com/gidoBOSSftw5731/Historical-ROA/proto"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// parseTimeParam reads a time from a query parameter as RFC 3339 or unix
+// seconds, "" is now
+func parseTimeParam(s string) (time.Time, error) {
+	if s == "" {
+		return time.Now(), nil
+	}
+	if unix, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return time.Unix(unix, 0), nil
+	}
+	return time.Parse(time.RFC3339, s)
+}
+
+// parsePrefixParam reads a prefix from a query parameter, masking off any
+// host bits
+func parsePrefixParam(s string) (netip.Prefix, error) {
+	p, err := netip.ParsePrefix(strings.TrimSpace(s))
+	if err != nil {
+		return p, err
+	}
+	return p.Masked(), nil
+}
+
+// setFullprefix fills in Fullprefix and Fullprefixrange from the rest
+func setFullprefix(results *pb.ResultsFromDB) {
+	results.Fullprefix = fmt.Sprintf("%v/%v", results.Prefix, results.Mask)
+	switch {
+	case results.Maxlen != results.Mask:
+		results.Fullprefixrange = fmt.Sprintf("%v/%v => %v",
+			results.Prefix, results.Mask, results.Maxlen)
+	case results.Maxlen == results.Mask:
+		results.Fullprefixrange = fmt.Sprintf("%v/%v", results.Prefix, results.Mask)
+	}
+}
+
+// entryResult converts an index entry for the API. The index doesn't keep
+// every time a ROA was seen, so the time arrays are left empty.
+func entryResult(e *roaEntry) *pb.ResultsFromDB {
+	results := &pb.ResultsFromDB{
+		ASN:    fmt.Sprintf("AS%d", e.ASN),
+		Asnum:  e.ASN,
+		Prefix: e.Prefix.Addr().String(),
+		Mask:   int32(e.Prefix.Bits()),
+		Maxlen: int32(e.MaxLen),
+		Ta:     e.TA,
+	}
+	setFullprefix(results)
+	return results
+}
+
+func entryResults(es []*roaEntry) []*pb.ResultsFromDB {
+	out := make([]*pb.ResultsFromDB, len(es))
+	for i, e := range es {
+		out[i] = entryResult(e)
+	}
+	return out
+}
+
+// timeArrays is ts as unix and RFC 3339 times, for the API
+func timeArrays(ts []time.Time) ([]int64, []string) {
+	unix := make([]int64, len(ts))
+	rfc := make([]string, len(ts))
+	for i, t := range ts {
+		unix[i] = t.Unix()
+		rfc[i] = t.Format(time.RFC3339)
+	}
+	return unix, rfc
+}
+
+func writeProto(w http.ResponseWriter, m proto.Message) {
+	w.Header().Set("Content-Type", "application/json")
+	fmt.Fprintln(w, protojson.Format(m))
+}
+
+// timeRange reads the from and to parameters, from defaults to the
+// beginning and to to now
+func timeRange(w http.ResponseWriter, r *http.Request) (from, to time.Time, ok bool) {
+	var err error
+	if s := r.FormValue("from"); s != "" {
+		from, err = parseTimeParam(s)
+		if err != nil {
+			ErrorHandler(w, r, 400, "Bad from time", err)
+			return from, to, false
+		}
+	}
+	to, err = parseTimeParam(r.FormValue("to"))
+	if err != nil {
+		ErrorHandler(w, r, 400, "Bad to time", err)
+		return from, to, false
+	}
+	return from, to, true
+}
+
+// indexOr503 returns the index, or says it isn't ready yet
+func indexOr503(w http.ResponseWriter, r *http.Request) *roaIndex {
+	idx := currentIndex()
+	if idx == nil {
+		ErrorHandler(w, r, 503, "The index isn't loaded yet", nil)
+	}
+	return idx
+}
+
+// indexLookup reads what a query wants from the index: the run in effect at
+// the time parameter, the trust anchors in ta, and whether to bridge TA
+// outages. With bridge=true, ROAs missing only because their whole TA
+// briefly vanished (and came back) are treated as still there. With
+// slurm=true, the SLURM file in effect at the time is applied.
+func indexLookup(w http.ResponseWriter, r *http.Request, idx *roaIndex) (lookup, bool) {
+	at, err := parseTimeParam(r.FormValue("time"))
+	if err != nil {
+		ErrorHandler(w, r, 400, "Bad time", err)
+		return lookup{}, false
+	}
+
+	idx.mu.RLock()
+	k := idx.runAt(at)
+	idx.mu.RUnlock()
+	if k < 0 {
+		ErrorHandler(w, r, 404, "No ROAs that long ago", nil)
+		return lookup{}, false
+	}
+
+	keep := taFilter(parseTAs(r.FormValue("ta")))
+	l := idx.lookup(k, keep, r.FormValue("bridge") == "true")
+
+	if r.FormValue("slurm") == "true" {
+		l.slurm, err = slurmAt(r.Context(), at)
+		if err != nil {
+			ErrorHandler(w, r, 500, "Can't load SLURM", err)
+			return lookup{}, false
+		}
+	}
+	return l, true
+}
+
+// coveringAPI lists the ROAs covering a prefix at a time, longest match
+// first.
+//
+//	/api/covering?prefix=192.0.2.0/24[&time=2021-07-11T10:00:00Z][&ta=arin,ripe][&bridge=true][&slurm=true]
+func coveringAPI(w http.ResponseWriter, r *http.Request) {
+	idx := indexOr503(w, r)
+	if idx == nil {
+		return
+	}
+
+	p, err := parsePrefixParam(r.FormValue("prefix"))
+	if err != nil {
+		ErrorHandler(w, r, 400, "Bad prefix", err)
+		return
+	}
+	l, ok := indexLookup(w, r, idx)
+	if !ok {
+		return
+	}
+
+	writeProto(w, &pb.ResultArr{Results: entryResults(idx.covering(p, l))})
+}
+
+// validityAPI does RFC 6811 origin validation of a route at a time.
+//
+//	/api/validity?prefix=192.0.2.0/24&asn=AS64496[&time=...][&ta=...][&bridge=true][&slurm=true]
+func validityAPI(w http.ResponseWriter, r *http.Request) {
+	idx := indexOr503(w, r)
+	if idx == nil {
+		return
+	}
+
+	p, err := parsePrefixParam(r.FormValue("prefix"))
+	if err != nil {
+		ErrorHandler(w, r, 400, "Bad prefix", err)
+		return
+	}
+	asn, err := parseASN(r.FormValue("asn"))
+	if err != nil {
+		ErrorHandler(w, r, 400, "Bad ASN", err)
+		return
+	}
+	l, ok := indexLookup(w, r, idx)
+	if !ok {
+		return
+	}
+
+	state, matched, unmatched := idx.validity(p, asn, l)
+	at := idx.runTime(l.run)
+	writeProto(w, &pb.Validity{
+		State:       state,
+		Prefix:      p.String(),
+		ASN:         fmt.Sprintf("AS%d", asn),
+		Unixtime:    at.Unix(),
+		RFC3339Time: at.Format(time.RFC3339),
+		Matched:     entryResults(matched),
+		Unmatched:   entryResults(unmatched),
+	})
+}
+
+// snapshotAPI lists every ROA as of a time. format gives it in something a
+// validator or router can load instead, to pin routers to an old set: one of
+// routinator, rpki-client, slurm, openbgpd, bird, bird2, cisco or juniper.
+//
+//	/api/snapshot[?time=...][&ta=...][&bridge=true][&slurm=true][&format=openbgpd]
+func snapshotAPI(w http.ResponseWriter, r *http.Request) {
+	idx := indexOr503(w, r)
+	if idx == nil {
+		return
+	}
+
+	var format exportFormat
+	if name := r.FormValue("format"); name != "" {
+		var ok bool
+		format, ok = exportFormats[name]
+		if !ok {
+			ErrorHandler(w, r, 400, "Unknown format, try one of "+exportFormatNames(), nil)
+			return
+		}
+	}
+	l, ok := indexLookup(w, r, idx)
+	if !ok {
+		return
+	}
+
+	roas := idx.snapshot(l)
+	if format.write == nil {
+		writeProto(w, &pb.ResultArr{Results: entryResults(roas)})
+		return
+	}
+	writeExport(w, r, format, l, roas, idx.runTime(l.run))
+}
+
+// indexDebug reports on the index, mostly how much memory it's using
+func indexDebug(w http.ResponseWriter, r *http.Request) {
+	stats := &pb.IndexStats{Heapbytes: heapBytes()}
+
+	if idx := currentIndex(); idx != nil {
+		stats.Loaded = true
+		stats.Bytes = idx.size()
+		stats.RFC3339Loaded = idx.loaded.Format(time.RFC3339)
+		stats.RFC3339Lastrun = idx.lastRun().Format(time.RFC3339)
+
+		idx.mu.RLock()
+		stats.Roas = int64(len(idx.byKey))
+		stats.Nodes = idx.nodes
+		stats.Spans = idx.spans
+		stats.Runs = int64(len(idx.runs))
+		idx.mu.RUnlock()
+	}
+
+	writeProto(w, stats)
+}
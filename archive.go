@@ -0,0 +1,152 @@
+package main
+
+import (
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/storage"
+)
+
+// snapshotArchive keeps the raw payloads we download, so history can be
+// reprocessed if the parser or schema changes and so we can prove what the
+// validator served. Anything that can store a blob under a key will do, eg.
+// a local directory or an object store bucket.
+type snapshotArchive interface {
+	// Put stores the already compressed data read from r under key.
+	Put(ctx context.Context, key string, r io.Reader) error
+}
+
+// archive is nil if archiving isn't configured
+var archive snapshotArchive
+
+// archiveFromEnv sets up the archive, returning nil if neither is set.
+//
+//	ARCHIVE_DIR     local directory to archive to
+//	ARCHIVE_BUCKET  GCS bucket (and optionally a path in it) to archive to
+//	                instead, eg. my-bucket/rarc. App Engine has no disk to
+//	                keep them on, so use this there.
+func archiveFromEnv() (snapshotArchive, error) {
+	dir, bucket := os.Getenv("ARCHIVE_DIR"), os.Getenv("ARCHIVE_BUCKET")
+	if dir != "" && bucket != "" {
+		return nil, fmt.Errorf("only one of ARCHIVE_DIR and ARCHIVE_BUCKET can be set")
+	}
+	if bucket != "" {
+		gcs, err := storage.NewClient(context.Background())
+		if err != nil {
+			return nil, err
+		}
+		name, prefix := bucket, ""
+		if i := strings.IndexByte(bucket, '/'); i != -1 {
+			name, prefix = bucket[:i], strings.Trim(bucket[i+1:], "/")+"/"
+		}
+		return gcsArchive{gcs.Bucket(name), prefix}, nil
+	}
+	if dir == "" {
+		return nil, nil
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	return dirArchive{dir}, nil
+}
+
+// snapshotKey is where a payload fetched at fetched with hash lives,
+// eg. 2021/07/10/20210710T150405Z-<sha256>.json.gz
+func snapshotKey(fetched time.Time, hash string) string {
+	fetched = fetched.UTC()
+	return fmt.Sprintf("%v/%v-%v.json.gz",
+		fetched.Format("2006/01/02"), fetched.Format("20060102T150405Z"), hash)
+}
+
+// archiveSpool gzips a payload into a temp file as it streams past, so it
+// can be archived once we know its hash without keeping it in memory
+type archiveSpool struct {
+	f  *os.File
+	zw *gzip.Writer
+}
+
+func newArchiveSpool() (*archiveSpool, error) {
+	f, err := ioutil.TempFile("", "rarc-")
+	if err != nil {
+		return nil, err
+	}
+	return &archiveSpool{f: f, zw: gzip.NewWriter(f)}, nil
+}
+
+func (a *archiveSpool) Write(p []byte) (int, error) {
+	return a.zw.Write(p)
+}
+
+// store puts the spooled payload in the archive, returning its key
+func (a *archiveSpool) store(ctx context.Context, fetched time.Time, hash string) (string, error) {
+	if err := a.zw.Close(); err != nil {
+		return "", err
+	}
+	if _, err := a.f.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+
+	key := snapshotKey(fetched, hash)
+	return key, archive.Put(ctx, key, a.f)
+}
+
+// Close gets rid of the temp file
+func (a *archiveSpool) Close() error {
+	a.f.Close()
+	return os.Remove(a.f.Name())
+}
+
+// dirArchive is a snapshotArchive in a local directory
+type dirArchive struct {
+	dir string
+}
+
+func (d dirArchive) Put(ctx context.Context, key string, r io.Reader) error {
+	path := filepath.Join(d.dir, filepath.FromSlash(key))
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	// write somewhere else first so a half written file never has the real name
+	tmp, err := ioutil.TempFile(filepath.Dir(path), ".tmp-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// gcsArchive is a snapshotArchive in a GCS bucket, with prefix in front of
+// every key
+type gcsArchive struct {
+	bucket *storage.BucketHandle
+	prefix string
+}
+
+func (g gcsArchive) Put(ctx context.Context, key string, r io.Reader) error {
+	// objects only appear once Close succeeds, so there are no half written
+	// ones either
+	w := g.bucket.Object(g.prefix + key).NewWriter(ctx)
+	w.ContentType = "application/json"
+	w.ContentEncoding = "gzip"
+	if _, err := io.Copy(w, r); err != nil {
+		w.Close()
+		return err
+	}
+	return w.Close()
+}
@@ -0,0 +1,223 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"cloud.google.com/go/bigquery"
+	pb "github.com/gidoBOSSftw5731/Historical-ROA/proto"
+	"github.com/gidoBOSSftw5731/log"
+	"google.golang.org/api/iterator"
+)
+
+// inputASPA is an ASPA from a validator, in Routinator's shape
+type inputASPA struct {
+	Customer  string   `json:"customer"`
+	Providers []string `json:"providers"`
+	Ta        string   `json:"ta,omitempty"`
+}
+
+// UnmarshalJSON takes Routinator's {"customer": "AS64496", "providers":
+// ["AS64497"]} and rpki-client's {"customer_asid": 64496, "providers":
+// [64497]}, or its older "provider_set" of {"provider_asid": 64497} objects.
+func (a *inputASPA) UnmarshalJSON(b []byte) error {
+	var v struct {
+		Customer     json.RawMessage   `json:"customer"`
+		CustomerASID json.RawMessage   `json:"customer_asid"`
+		Providers    []json.RawMessage `json:"providers"`
+		ProviderSet  []json.RawMessage `json:"provider_set"`
+		Ta           string            `json:"ta"`
+	}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+
+	customer := v.Customer
+	if len(customer) == 0 {
+		customer = v.CustomerASID
+	}
+	var err error
+	if a.Customer, err = jsonASN(customer); err != nil {
+		return err
+	}
+	a.Ta = v.Ta
+
+	a.Providers = nil
+	for _, p := range append(v.Providers, v.ProviderSet...) {
+		if len(p) > 0 && p[0] == '{' {
+			var o struct {
+				Asn          json.RawMessage `json:"asn"`
+				ProviderASID json.RawMessage `json:"provider_asid"`
+			}
+			if err := json.Unmarshal(p, &o); err != nil {
+				return err
+			}
+			p = o.Asn
+			if len(p) == 0 {
+				p = o.ProviderASID
+			}
+		}
+		asn, err := jsonASN(p)
+		if err != nil {
+			return err
+		}
+		a.Providers = append(a.Providers, asn)
+	}
+	return nil
+}
+
+// aspaRow is a row of aspas_arr, with the same history as roaRow. Providers
+// are sorted so the same set always looks the same.
+type aspaRow struct {
+	Customer       uint32      `bigquery:"customer"`
+	Providers      []uint32    `bigquery:"providers"`
+	Ta             string      `bigquery:"ta"`
+	Inserttimes    []time.Time `bigquery:"inserttimes"`
+	Generatedtimes []time.Time `bigquery:"generatedtimes"`
+}
+
+// aspaParam is an ASPA to merge, as a query parameter
+type aspaParam struct {
+	Customer  int64
+	Providers []int64
+	Ta        string
+}
+
+// normaliseASPA parses the ASNs in a and sorts and dedups the providers
+func normaliseASPA(a inputASPA) (aspaParam, error) {
+	customer, err := parseASN(a.Customer)
+	if err != nil {
+		return aspaParam{}, err
+	}
+
+	seen := make(map[uint32]bool)
+	out := aspaParam{Customer: int64(customer), Ta: a.Ta, Providers: []int64{}}
+	for _, p := range a.Providers {
+		asn, err := parseASN(p)
+		if err != nil {
+			return aspaParam{}, err
+		}
+		if !seen[asn] {
+			seen[asn] = true
+			out.Providers = append(out.Providers, int64(asn))
+		}
+	}
+	sort.Slice(out.Providers, func(i, j int) bool { return out.Providers[i] < out.Providers[j] })
+	return out, nil
+}
+
+// mergeASPAs adds an update's ASPAs to aspas_arr. There are few enough that
+// they go straight in as a query parameter rather than through staging.
+func mergeASPAs(ctx context.Context, aspas []inputASPA, fetched, generated time.Time) (int, error) {
+	var params []aspaParam
+	seen := make(map[string]bool)
+	for _, a := range aspas {
+		p, err := normaliseASPA(a)
+		if err != nil {
+			log.Traceln("skipping bad ASPA ", a, ": ", err)
+			continue
+		}
+		// MERGE can't take the same one twice
+		key := fmt.Sprint(p)
+		if !seen[key] {
+			seen[key] = true
+			params = append(params, p)
+		}
+	}
+	if len(params) == 0 {
+		return 0, nil
+	}
+
+	if _, err := ensureTable(ctx, "aspas_arr", aspaRow{}); err != nil {
+		return 0, fmt.Errorf("error updating aspas_arr: %v", err)
+	}
+
+	query := client.Query(`MERGE historical.aspas_arr arr
+	USING (SELECT * FROM UNNEST(@aspas)) a
+	ON arr.customer = a.Customer AND arr.ta = a.Ta
+	AND TO_JSON_STRING(arr.providers) = TO_JSON_STRING(a.Providers)
+	WHEN MATCHED THEN
+		UPDATE SET inserttimes = ` + addTimeSQL("arr.inserttimes") + `,
+		generatedtimes = ` + addGeneratedSQL("arr.inserttimes", "arr.generatedtimes") + `
+	WHEN NOT MATCHED BY TARGET THEN
+		INSERT (customer, providers, ta, inserttimes, generatedtimes)
+		VALUES (a.Customer, a.Providers, a.Ta, [@fetched], [@generated])`)
+	query.Parameters = []bigquery.QueryParameter{
+		{Name: "aspas", Value: params},
+		{Name: "fetched", Value: fetched},
+		{Name: "generated", Value: generated},
+	}
+	_, err := runQuery(ctx, query)
+	return len(params), err
+}
+
+// aspaAPI lists the history of ASPAs for a customer, or that list a
+// provider.
+//
+//	/api/aspa?customer=AS64496
+//	/api/aspa?provider=AS64497
+func aspaAPI(w http.ResponseWriter, r *http.Request) {
+	var where string
+	var asn uint32
+	var err error
+	switch {
+	case r.FormValue("customer") != "":
+		asn, err = parseASN(r.FormValue("customer"))
+		where = "customer = @asn"
+	case r.FormValue("provider") != "":
+		asn, err = parseASN(r.FormValue("provider"))
+		where = "@asn IN UNNEST(providers)"
+	default:
+		ErrorHandler(w, r, 400, "Need a customer or provider", nil)
+		return
+	}
+	if err != nil {
+		ErrorHandler(w, r, 400, "Bad ASN", err)
+		return
+	}
+	params := []bigquery.QueryParameter{{Name: "asn", Value: int64(asn)}}
+	if tas := parseTAs(r.FormValue("ta")); len(tas) > 0 {
+		cond, param := taCondition(tas)
+		where += " AND " + cond
+		params = append(params, param)
+	}
+
+	query := client.Query(`SELECT customer, providers, ta, inserttimes
+	FROM historical.aspas_arr WHERE ` + where)
+	query.Parameters = params
+	it, err := runQuery(r.Context(), query)
+	if err != nil {
+		ErrorHandler(w, r, 500, "Error with query", err)
+		return
+	}
+
+	var out pb.ASPAArr
+	for {
+		var row aspaRow
+		err := it.Next(&row)
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			ErrorHandler(w, r, 500, "Error with query", err)
+			return
+		}
+
+		a := &pb.ASPA{
+			Customer:      fmt.Sprintf("AS%d", row.Customer),
+			Customerasnum: row.Customer,
+			Ta:            row.Ta,
+		}
+		for _, p := range row.Providers {
+			a.Providers = append(a.Providers, fmt.Sprintf("AS%d", p))
+		}
+		a.Unixtimearr, a.RFC3339Timearr = timeArrays(row.Inserttimes)
+		out.Aspas = append(out.Aspas, a)
+	}
+
+	writeProto(w, &out)
+}
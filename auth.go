@@ -0,0 +1,130 @@
+package main
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gidoBOSSftw5731/log"
+)
+
+// hmacWindow is how far a signed request's timestamp may be from our clock,
+// and signedBodyMax is the biggest body a signed request can have (the
+// biggest any of the handlers take)
+const (
+	hmacWindow    = 5 * time.Minute
+	signedBodyMax = 10 << 20
+)
+
+// updateAuth decides who is allowed to trigger an update.
+type updateAuth struct {
+	// appEngine trusts the X-Appengine-Cron header. App Engine strips
+	// X-Appengine-* headers from outside requests so this is only safe there.
+	appEngine bool
+	// token is a shared secret sent as "Authorization: Bearer <token>"
+	token []byte
+	// hmacKey signs requests, see signedOK
+	hmacKey []byte
+}
+
+// updateAuthFromEnv reads the /update auth config from the environment.
+//
+//	UPDATE_TOKEN     shared secret accepted as a bearer token
+//	UPDATE_HMAC_KEY  key for HMAC signed requests
+//
+// X-Appengine-Cron is accepted when running on App Engine standard.
+func updateAuthFromEnv() *updateAuth {
+	a := &updateAuth{
+		appEngine: os.Getenv("GAE_ENV") == "standard",
+		token:     []byte(os.Getenv("UPDATE_TOKEN")),
+		hmacKey:   []byte(os.Getenv("UPDATE_HMAC_KEY")),
+	}
+	if !a.appEngine && len(a.token) == 0 && len(a.hmacKey) == 0 {
+		log.Errorln("not on App Engine and no UPDATE_TOKEN or UPDATE_HMAC_KEY set, /update will refuse everything")
+	}
+	return a
+}
+
+// require wraps h so it's only called for authorised requests, anyone else
+// gets a 403.
+func (a *updateAuth) require(h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !a.allowed(r) {
+			ErrorHandler(w, r, 403, "not allowed to update",
+				fmt.Errorf("unauthorised %v from %v", r.URL.Path, r.RemoteAddr))
+			return
+		}
+		h(w, r)
+	}
+}
+
+func (a *updateAuth) allowed(r *http.Request) bool {
+	if a.appEngine && r.Header.Get("X-Appengine-Cron") == "true" {
+		return true
+	}
+	if len(a.token) != 0 && a.bearerOK(r) {
+		return true
+	}
+	if len(a.hmacKey) != 0 && a.signedOK(r) {
+		return true
+	}
+	return false
+}
+
+func (a *updateAuth) bearerOK(r *http.Request) bool {
+	h := r.Header.Get("Authorization")
+	if !strings.HasPrefix(h, "Bearer ") {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(strings.TrimPrefix(h, "Bearer ")), a.token) == 1
+}
+
+// signedOK checks an HMAC signed request. The client sends
+//
+//	X-Update-Timestamp: <unix seconds>
+//	X-Update-Signature: hex(HMAC-SHA256(key, timestamp + "\n" + method + "\n" + request URI + "\n" + hex(SHA256(body))))
+//
+// and the timestamp has to be within hmacWindow of now. The body's signed so
+// a captured request can't be resent with a different one. It's read here
+// and put back for the handler.
+func (a *updateAuth) signedOK(r *http.Request) bool {
+	ts := r.Header.Get("X-Update-Timestamp")
+	sig, err := hex.DecodeString(r.Header.Get("X-Update-Signature"))
+	if ts == "" || err != nil || len(sig) == 0 {
+		return false
+	}
+
+	unix, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return false
+	}
+	if d := time.Since(time.Unix(unix, 0)); d > hmacWindow || d < -hmacWindow {
+		return false
+	}
+
+	body, err := ioutil.ReadAll(io.LimitReader(r.Body, signedBodyMax+1))
+	if err != nil || len(body) > signedBodyMax {
+		return false
+	}
+	r.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	return hmac.Equal(sig, signRequest(a.hmacKey, ts, r.Method, r.URL.RequestURI(), body))
+}
+
+// signRequest makes the signature signedOK expects
+func signRequest(key []byte, ts, method, uri string, body []byte) []byte {
+	sum := sha256.Sum256(body)
+	mac := hmac.New(sha256.New, key)
+	fmt.Fprintf(mac, "%s\n%s\n%s\n%x", ts, method, uri, sum)
+	return mac.Sum(nil)
+}
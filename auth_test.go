@@ -0,0 +1,54 @@
+package main
+
+import (
+	"encoding/hex"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// signedRequest makes a request signed with key the way signedOK wants
+func signedRequest(key []byte, method, target, body string) *http.Request {
+	r := httptest.NewRequest(method, target, strings.NewReader(body))
+	ts := strconv.FormatInt(time.Now().Unix(), 10)
+	r.Header.Set("X-Update-Timestamp", ts)
+	r.Header.Set("X-Update-Signature",
+		hex.EncodeToString(signRequest(key, ts, method, r.URL.RequestURI(), []byte(body))))
+	return r
+}
+
+func TestSignedRequest(t *testing.T) {
+	a := &updateAuth{hmacKey: []byte("key")}
+	var got string
+	h := a.require(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := ioutil.ReadAll(r.Body)
+		got = string(b)
+	})
+
+	body := `{"url": "https://192.0.2.1/hook"}`
+	w := httptest.NewRecorder()
+	h(w, signedRequest(a.hmacKey, "POST", "/api/watchlists", body))
+	if w.Code != 200 || got != body {
+		t.Errorf("signed request got %v and the handler read %q", w.Code, got)
+	}
+
+	// the same signature with another body is a replay
+	r := signedRequest(a.hmacKey, "POST", "/api/watchlists", body)
+	r.Body = ioutil.NopCloser(strings.NewReader(`{"url": "https://198.51.100.1/mine"}`))
+	w = httptest.NewRecorder()
+	got = ""
+	h(w, r)
+	if w.Code != 403 || got != "" {
+		t.Errorf("request with a swapped body got %v", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	h(w, signedRequest([]byte("other key"), "POST", "/api/watchlists", body))
+	if w.Code != 403 {
+		t.Errorf("request signed with the wrong key got %v", w.Code)
+	}
+}
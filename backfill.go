@@ -0,0 +1,277 @@
+package main
+
+import (
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/bigquery"
+	"github.com/gidoBOSSftw5731/log"
+	"github.com/ulikunitz/xz"
+	"google.golang.org/api/iterator"
+)
+
+// snapshotFile is an old validator output file waiting to be imported
+type snapshotFile struct {
+	path string
+	at   time.Time
+}
+
+// fileTimeRe finds a timestamp in a file name, eg. 20200718T101002Z,
+// 2020-07-18T10:10:02Z, 2020-07-18_10-10, or just 20200718
+var fileTimeRe = regexp.MustCompile(
+	`(\d{4})-?(\d{2})-?(\d{2})(?:[T_. -]?(\d{2})[:-]?(\d{2})(?:[:-]?(\d{2}))?)?`)
+
+// backfillCmd imports a directory of old Routinator/rpki-client output into
+// roas_arr, oldest first, using each file's timestamp as the time it was seen.
+func backfillCmd(args []string) error {
+	fs := flag.NewFlagSet("backfill", flag.ExitOnError)
+	dryRun := fs.Bool("n", false, "parse everything but don't merge anything")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: Historical-ROA backfill [-n] <dir>")
+		fmt.Fprintln(fs.Output(), "imports .json and .csv files, optionally .gz or .xz compressed.")
+		fmt.Fprintln(fs.Output(), "the time is taken from the file name if it has one, otherwise its mtime.")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return errors.New("need exactly one directory")
+	}
+
+	snaps, err := findSnapshots(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	log.Debugf("found %v snapshots", len(snaps))
+
+	ctx := context.Background()
+
+	done, err := importedHashes(ctx)
+	if err != nil {
+		return fmt.Errorf("can't get runs: %v", err)
+	}
+
+	for _, s := range snaps {
+		hash, err := hashSnapshot(s.path)
+		if err != nil {
+			return fmt.Errorf("%v: %v", s.path, err)
+		}
+		if _, ok := done[hash]; ok {
+			log.Debugf("%v already imported, skipping", s.path)
+			continue
+		}
+		if *dryRun {
+			log.Debugf("%v: would import as %v", s.path, s.at)
+			continue
+		}
+
+		err = backfillOne(ctx, s, hash)
+		if err != nil {
+			return fmt.Errorf("%v: %v", s.path, err)
+		}
+		done[hash] = struct{}{}
+	}
+
+	return nil
+}
+
+// backfillOne merges one snapshot, recording it in the runs table like
+// a normal update
+func backfillOne(ctx context.Context, s snapshotFile, hash string) (err error) {
+	run := newUpdateRun("file://" + s.path)
+	defer func() { run.finish(ctx, err) }()
+	run.Fetched = s.at
+	run.PayloadHash = hash
+
+	r, err := openSnapshot(s.path)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	staging, err := newStagingWriter(ctx, "buf_backfill", run.ID)
+	if err != nil {
+		return err
+	}
+
+	var doc inputROAArr
+	switch snapshotFormat(s.path) {
+	case "json":
+		doc, run.ROAs, err = decodeRARC(r, stagingChunk, staging.Write)
+	case "csv":
+		run.ROAs, err = decodeCSV(r, stagingChunk, staging.Write)
+	}
+	if err != nil {
+		staging.Close()
+		return err
+	}
+	run.Staging, err = staging.Close()
+	if err != nil {
+		return err
+	}
+	log.Debugf("%v: %v ROAs at %v", s.path, run.ROAs, s.at)
+
+	generated, ok := doc.Metadata.generatedAt()
+	if ok {
+		run.Generated = bigquery.NullTimestamp{Timestamp: generated, Valid: true}
+	} else {
+		generated = s.at
+	}
+
+	if err := mergeStaged(ctx, "buf_backfill", s.at, generated); err != nil {
+		return err
+	}
+	if err := mergeExtras(ctx, run, doc, s.at, generated); err != nil {
+		return err
+	}
+	run.Provenance = staging.provenance
+	if run.Provenance > 0 {
+		return mergeProvenance(ctx, "buf_backfill", s.at)
+	}
+	return nil
+}
+
+// importedHashes returns the payload hashes of every successful run, so
+// running a backfill twice doesn't count the same snapshot twice
+func importedHashes(ctx context.Context) (map[string]struct{}, error) {
+	if _, err := ensureTable(ctx, "runs", updateRun{}); err != nil {
+		return nil, err
+	}
+
+	it, err := runQuery(ctx, client.Query(
+		`SELECT DISTINCT PayloadHash FROM historical.runs WHERE Status = "ok" AND PayloadHash IS NOT NULL`))
+	if err != nil {
+		return nil, err
+	}
+
+	done := make(map[string]struct{})
+	for {
+		var row struct{ PayloadHash string }
+		err := it.Next(&row)
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		done[row.PayloadHash] = struct{}{}
+	}
+	return done, nil
+}
+
+// findSnapshots lists the importable files under dir, oldest first
+func findSnapshots(dir string) ([]snapshotFile, error) {
+	var snaps []snapshotFile
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || snapshotFormat(path) == "" {
+			return nil
+		}
+
+		at, ok := timeFromName(info.Name())
+		if !ok {
+			at = info.ModTime()
+		}
+		snaps = append(snaps, snapshotFile{path, at})
+		return nil
+	})
+
+	sort.SliceStable(snaps, func(i, j int) bool { return snaps[i].at.Before(snaps[j].at) })
+	return snaps, err
+}
+
+// snapshotFormat is "json" or "csv" depending on path's extension once any
+// compression extension is taken off, or "" if we can't read it
+func snapshotFormat(path string) string {
+	path = strings.TrimSuffix(strings.TrimSuffix(strings.ToLower(path), ".gz"), ".xz")
+	switch filepath.Ext(path) {
+	case ".json":
+		return "json"
+	case ".csv":
+		return "csv"
+	}
+	return ""
+}
+
+// timeFromName pulls a UTC timestamp out of a file name
+func timeFromName(name string) (time.Time, bool) {
+	m := fileTimeRe.FindStringSubmatch(name)
+	if m == nil {
+		return time.Time{}, false
+	}
+
+	var n [6]int
+	for i, s := range m[1:] {
+		n[i], _ = strconv.Atoi(s)
+	}
+	t := time.Date(n[0], time.Month(n[1]), n[2], n[3], n[4], n[5], 0, time.UTC)
+	// Date normalises nonsense like month 13, which means it wasn't a date
+	if t.Year() != n[0] || int(t.Month()) != n[1] || t.Day() != n[2] ||
+		n[3] > 23 || n[4] > 59 || n[5] > 59 {
+		return time.Time{}, false
+	}
+	return t, true
+}
+
+// openSnapshot opens a snapshot file, decompressing it if needed
+func openSnapshot(path string) (io.ReadCloser, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".gz":
+		zr, err := gzip.NewReader(f)
+		if err != nil {
+			f.Close()
+			return nil, err
+		}
+		return readCloser{zr, f}, nil
+	case ".xz":
+		xr, err := xz.NewReader(f)
+		if err != nil {
+			f.Close()
+			return nil, err
+		}
+		return readCloser{xr, f}, nil
+	}
+	return f, nil
+}
+
+// readCloser reads from a decompressor but closes the file underneath it
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// hashSnapshot is the hex sha256 of a snapshot file's uncompressed contents,
+// the same as PayloadHash for a live update
+func hashSnapshot(path string) (string, error) {
+	r, err := openSnapshot(path)
+	if err != nil {
+		return "", err
+	}
+	defer r.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, r); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/bigquery"
+	"github.com/gidoBOSSftw5731/log"
+	"google.golang.org/api/iterator"
+)
+
+// changeRow is a row of the changes table, a ROA an update added or removed
+// compared to the update before it
+type changeRow struct {
+	RunID  string
+	Time   time.Time
+	Added  bool
+	Asnum  int64
+	Prefix string
+	Maxlen int
+	Ta     string
+	// Outage is set if the TA looked down in this update or the one before,
+	// so it's probably not a real change. NULL in rows from before it was
+	// added.
+	Outage bool
+}
+
+// Save inserts the change with the run and ROA as the insert ID, so retried
+// inserts don't double up changes
+func (c changeRow) Save() (map[string]bigquery.Value, string, error) {
+	schema, err := bigquery.InferSchema(c)
+	if err != nil {
+		return nil, "", err
+	}
+	id := fmt.Sprintf("%v-%d-%v-%d-%v", c.RunID, c.Asnum, c.Prefix, c.Maxlen, c.Ta)
+	return (&bigquery.StructSaver{Schema: schema, Struct: c, InsertID: id}).Save()
+}
+
+// entry is the change as an index entry, for matching and the API
+func (c changeRow) entry() (*roaEntry, error) {
+	p, err := netip.ParsePrefix(c.Prefix)
+	if err != nil {
+		return nil, err
+	}
+	return &roaEntry{ASN: uint32(c.Asnum), Prefix: p.Masked(), MaxLen: c.Maxlen, TA: c.Ta}, nil
+}
+
+// findChanges works out what the merged update added and removed, records it
+// in the changes table and counts it on run. Like findEvents, backfills
+// don't.
+func findChanges(ctx context.Context, run *updateRun) ([]changeRow, error) {
+	down, err := outageTAs(ctx, run)
+	if err != nil {
+		// better to have them all look real than not have them
+		log.Errorln("can't check changes for TA outages: ", err)
+	}
+
+	query := client.Query(`WITH prev AS (
+		SELECT MAX(t) AS t FROM historical.roas_arr, UNNEST(inserttimes) t WHERE t < @fetched
+	)
+	SELECT ` + asnumColumn + ` AS asnum, CONCAT(prefix, '/', CAST(mask AS STRING)) AS prefix,
+		maxlen, ta, @fetched IN UNNEST(inserttimes) AS added
+	FROM historical.roas_arr
+	WHERE (SELECT t FROM prev) IS NOT NULL
+		AND (@fetched IN UNNEST(inserttimes)) != IFNULL((SELECT t FROM prev) IN UNNEST(inserttimes), FALSE)`)
+	query.Parameters = []bigquery.QueryParameter{{Name: "fetched", Value: run.Fetched}}
+	it, err := runQuery(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []changeRow
+	for {
+		var row changeRow
+		err := it.Next(&row)
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		row.RunID, row.Time = run.ID, run.Fetched
+		row.Outage = down[strings.ToLower(row.Ta)]
+		if row.Added {
+			run.Added++
+		} else {
+			run.Removed++
+		}
+		changes = append(changes, row)
+	}
+	if len(changes) == 0 {
+		return nil, nil
+	}
+
+	table, err := ensureTable(ctx, "changes", changeRow{})
+	if err != nil {
+		return nil, err
+	}
+	for i := 0; i < len(changes); i += stagingChunk {
+		end := i + stagingChunk
+		if end > len(changes) {
+			end = len(changes)
+		}
+		if err := table.Inserter().Put(ctx, changes[i:end]); err != nil {
+			return nil, err
+		}
+	}
+	return changes, nil
+}
@@ -0,0 +1,367 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	netmail "net/mail"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/bigquery"
+	pb "github.com/gidoBOSSftw5731/Historical-ROA/proto"
+	"github.com/gidoBOSSftw5731/log"
+	"google.golang.org/api/iterator"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// digestStarts are when the latest period of each frequency of digest
+// started before t. Periods start at midnight UTC, on Mondays for weekly
+// ones, so digests don't drift later with each update.
+var digestStarts = map[string]func(t time.Time) time.Time{
+	"daily": day,
+	"weekly": func(t time.Time) time.Time {
+		d := day(t)
+		return d.AddDate(0, 0, -(int(d.Weekday())+6)%7)
+	},
+}
+
+// subscriberRow is a row of the subscribers table. Like watchlists they're
+// changed with DML.
+type subscriberRow struct {
+	ID        string
+	Name      string
+	Email     string
+	ASNs      []int64
+	Prefixes  []string
+	TAs       []string
+	Frequency string
+	Created   time.Time
+	// LastSent is when the last digest covered up to, NULL before the first
+	LastSent bigquery.NullTimestamp
+}
+
+// filter is what the subscriber wants, as a watchlist filter
+func (s *subscriberRow) filter() (*watchRow, error) {
+	f := &watchRow{ASNs: s.ASNs, Prefixes: s.Prefixes, TAs: s.TAs}
+	return f, f.parse()
+}
+
+// since is when the subscriber's next digest starts from
+func (s *subscriberRow) since() time.Time {
+	if s.LastSent.Valid {
+		return s.LastSent.Timestamp
+	}
+	return s.Created
+}
+
+func subscribers(ctx context.Context) ([]*subscriberRow, error) {
+	if _, err := ensureTable(ctx, "subscribers", subscriberRow{}); err != nil {
+		return nil, err
+	}
+	it, err := runQuery(ctx, client.Query(`SELECT * FROM historical.subscribers ORDER BY Created`))
+	if err != nil {
+		return nil, err
+	}
+
+	var out []*subscriberRow
+	for {
+		var row subscriberRow
+		err := it.Next(&row)
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, &row)
+	}
+	return out, nil
+}
+
+// digestChanges is the changes to what f covers in updates after from up to
+// and including to, oldest first, with TA outages bridged like the feeds
+func (idx *roaIndex) digestChanges(f *watchRow, from, to time.Time) []roaChange {
+	seen := make(map[*roaEntry]bool)
+	var entries []*roaEntry
+	add := func(es []*roaEntry) {
+		for _, e := range es {
+			if !seen[e] && f.covers(e) {
+				seen[e] = true
+				entries = append(entries, e)
+			}
+		}
+	}
+	for _, asn := range f.ASNs {
+		add(idx.asnEntries(uint32(asn)))
+	}
+	for _, p := range f.prefixes {
+		add(idx.prefixEntries(p))
+	}
+
+	gaps, _ := idx.bridgeable()
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	var out []roaChange
+	for _, e := range entries {
+		for _, c := range e.changes(gaps, len(idx.runs)) {
+			if at := idx.runs[c.run]; at.After(from) && !at.After(to) {
+				out = append(out, c)
+			}
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].run != out[j].run {
+			return out[i].run < out[j].run
+		}
+		if out[i].added != out[j].added {
+			return out[i].added
+		}
+		return out[i].e.Prefix.String() < out[j].e.Prefix.String()
+	})
+	return out
+}
+
+// digestBody is the text of a digest, one block per update
+func (idx *roaIndex) digestBody(s *subscriberRow, changes []roaChange, from, to time.Time) string {
+	var b strings.Builder
+	var watching []string
+	for _, asn := range s.ASNs {
+		watching = append(watching, fmt.Sprintf("AS%d", asn))
+	}
+	watching = append(watching, s.Prefixes...)
+	fmt.Fprintf(&b, "ROA changes for %v between %v and %v.\n",
+		strings.Join(watching, ", "), from.UTC().Format(time.RFC3339), to.UTC().Format(time.RFC3339))
+	if len(s.TAs) > 0 {
+		fmt.Fprintf(&b, "Only ROAs from %v are included.\n", strings.Join(s.TAs, ", "))
+	}
+	fmt.Fprint(&b, "+ is a ROA that was added, - one that was removed.\n")
+
+	run := -1
+	for _, c := range changes {
+		if c.run != run {
+			run = c.run
+			fmt.Fprintf(&b, "\n%v\n", idx.runTime(run).UTC().Format(time.RFC3339))
+		}
+		sign := "-"
+		if c.added {
+			sign = "+"
+		}
+		res := entryResult(c.e)
+		fmt.Fprintf(&b, "  %v %v %v (%v)\n", sign, res.ASN, res.Fullprefixrange, c.e.TA)
+	}
+	return b.String()
+}
+
+// mailDigests sends the digests that are due after an update. They come
+// from the index, so they wait for it if it isn't loaded yet; nothing's lost,
+// they cover everything since the last one when they do go.
+func mailDigests(ctx context.Context, idx *roaIndex) {
+	if mail == nil {
+		return
+	}
+	if idx == nil {
+		log.Errorln("the index isn't loaded, digests can't go out until it is")
+		return
+	}
+	subs, err := subscribers(ctx)
+	if err != nil {
+		log.Errorln("can't load subscribers: ", err)
+		return
+	}
+	idx.sendDigests(ctx, subs, recordDigest)
+}
+
+// recordDigest remembers that s has had its digest up to to
+func recordDigest(ctx context.Context, s *subscriberRow, to time.Time) error {
+	query := client.Query(`UPDATE historical.subscribers SET LastSent = @to WHERE ID = @id`)
+	query.Parameters = []bigquery.QueryParameter{{Name: "to", Value: to}, {Name: "id", Value: s.ID}}
+	_, err := runQuery(ctx, query)
+	return err
+}
+
+// sendDigests emails every subscriber in subs whose digest is due, if there
+// were any changes for them, and records it with sent. A digest covers a
+// whole period, and is due once the index has an update after it ends.
+func (idx *roaIndex) sendDigests(ctx context.Context, subs []*subscriberRow,
+	sent func(context.Context, *subscriberRow, time.Time) error) {
+	last := idx.lastRun()
+	for _, s := range subs {
+		start, ok := digestStarts[s.Frequency]
+		if !ok {
+			continue
+		}
+		from, to := s.since(), start(last)
+		if !to.After(from) {
+			continue
+		}
+		f, err := s.filter()
+		if err != nil {
+			log.Errorf("subscriber %v has a bad prefix: %v", s.ID, err)
+			continue
+		}
+
+		changes := idx.digestChanges(f, from, to)
+		if len(changes) > 0 {
+			subject := fmt.Sprintf("ROA changes %v: %v", s.Frequency, s.Name)
+			if s.Name == "" {
+				subject = fmt.Sprintf("ROA changes %v", s.Frequency)
+			}
+			if err := mail.Send(ctx, s.Email, subject, idx.digestBody(s, changes, from, to)); err != nil {
+				log.Errorf("can't send digest to subscriber %v: %v", s.ID, err)
+				continue
+			}
+		}
+
+		if err := sent(ctx, s, to); err != nil {
+			log.Errorf("can't record digest for subscriber %v: %v", s.ID, err)
+		}
+	}
+}
+
+func subscriberResult(s *subscriberRow) *pb.Subscriber {
+	out := &pb.Subscriber{
+		Id:             s.ID,
+		Name:           s.Name,
+		Email:          s.Email,
+		Prefixes:       s.Prefixes,
+		Tas:            s.TAs,
+		Frequency:      s.Frequency,
+		Created:        s.Created.Unix(),
+		RFC3339Created: s.Created.Format(time.RFC3339),
+	}
+	for _, asn := range s.ASNs {
+		out.Asns = append(out.Asns, fmt.Sprintf("AS%d", asn))
+	}
+	if s.LastSent.Valid {
+		out.Lastsent = s.LastSent.Timestamp.Unix()
+		out.RFC3339Lastsent = s.LastSent.Timestamp.Format(time.RFC3339)
+	}
+	return out
+}
+
+// newSubscriber checks a subscriber from the API and makes a row for it
+func newSubscriber(in *pb.Subscriber) (*subscriberRow, error) {
+	addr, err := netmail.ParseAddress(in.Email)
+	if err != nil {
+		return nil, fmt.Errorf("bad email %q", in.Email)
+	}
+	if in.Frequency == "" {
+		in.Frequency = "daily"
+	}
+	if _, ok := digestStarts[in.Frequency]; !ok {
+		return nil, fmt.Errorf("bad frequency %q, try daily or weekly", in.Frequency)
+	}
+	if len(in.Asns) == 0 && len(in.Prefixes) == 0 {
+		return nil, fmt.Errorf("need some ASNs or prefixes")
+	}
+
+	now := time.Now()
+	s := &subscriberRow{
+		ID:        strconv.FormatInt(now.UnixNano(), 10),
+		Name:      in.Name,
+		Email:     addr.Address,
+		TAs:       parseTAs(strings.Join(in.Tas, ",")),
+		Frequency: in.Frequency,
+		Created:   now,
+	}
+	for _, a := range in.Asns {
+		asn, err := parseASN(a)
+		if err != nil {
+			return nil, err
+		}
+		s.ASNs = append(s.ASNs, int64(asn))
+	}
+	for _, p := range in.Prefixes {
+		prefix, err := parsePrefixParam(p)
+		if err != nil {
+			return nil, err
+		}
+		s.Prefixes = append(s.Prefixes, prefix.String())
+	}
+	return s, nil
+}
+
+// subscriberAPI manages digest subscribers like watchlistAPI does
+// watchlists. frequency is daily (the default) or weekly, days are UTC and
+// weeks start on Monday.
+//
+//	GET    /api/subscribers
+//	POST   /api/subscribers {"name": ..., "email": ..., "asns": [...], "prefixes": [...], "tas": [...], "frequency": "weekly"}
+//	DELETE /api/subscribers?id=...
+func subscriberAPI(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	switch r.Method {
+	case http.MethodGet:
+		subs, err := subscribers(ctx)
+		if err != nil {
+			ErrorHandler(w, r, 500, "Can't load subscribers", err)
+			return
+		}
+		var out pb.Subscribers
+		for _, s := range subs {
+			out.Subscribers = append(out.Subscribers, subscriberResult(s))
+		}
+		writeProto(w, &out)
+
+	case http.MethodPost:
+		body, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, 1<<20))
+		if err != nil {
+			ErrorHandler(w, r, 400, "Can't read subscriber", err)
+			return
+		}
+		var in pb.Subscriber
+		if err := protojson.Unmarshal(body, &in); err != nil {
+			ErrorHandler(w, r, 400, "Bad subscriber", err)
+			return
+		}
+		s, err := newSubscriber(&in)
+		if err != nil {
+			ErrorHandler(w, r, 400, "Bad subscriber", err)
+			return
+		}
+
+		if _, err := ensureTable(ctx, "subscribers", subscriberRow{}); err != nil {
+			ErrorHandler(w, r, 500, "Can't store subscriber", err)
+			return
+		}
+		query := client.Query(`INSERT INTO historical.subscribers
+		(ID, Name, Email, ASNs, Prefixes, TAs, Frequency, Created)
+		VALUES (@id, @name, @email, @asns, @prefixes, @tas, @frequency, @created)`)
+		query.Parameters = []bigquery.QueryParameter{
+			{Name: "id", Value: s.ID},
+			{Name: "name", Value: s.Name},
+			{Name: "email", Value: s.Email},
+			{Name: "asns", Value: append([]int64{}, s.ASNs...)},
+			{Name: "prefixes", Value: append([]string{}, s.Prefixes...)},
+			{Name: "tas", Value: append([]string{}, s.TAs...)},
+			{Name: "frequency", Value: s.Frequency},
+			{Name: "created", Value: s.Created},
+		}
+		if _, err := runQuery(ctx, query); err != nil {
+			ErrorHandler(w, r, 500, "Can't store subscriber", err)
+			return
+		}
+		writeProto(w, subscriberResult(s))
+
+	case http.MethodDelete:
+		id := r.FormValue("id")
+		if id == "" {
+			ErrorHandler(w, r, 400, "Need an id", nil)
+			return
+		}
+		query := client.Query(`DELETE FROM historical.subscribers WHERE ID = @id`)
+		query.Parameters = []bigquery.QueryParameter{{Name: "id", Value: id}}
+		if _, err := runQuery(ctx, query); err != nil {
+			ErrorHandler(w, r, 500, "Can't delete subscriber", err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		ErrorHandler(w, r, 405, "GET, POST or DELETE", nil)
+	}
+}
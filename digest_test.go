@@ -0,0 +1,105 @@
+package main
+
+import (
+	"context"
+	"io/ioutil"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSendDigests(t *testing.T) {
+	dir := t.TempDir()
+	for _, d := range []string{"tmp", "new", "cur"} {
+		if err := os.Mkdir(filepath.Join(dir, d), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	defer func(m mailer) { mail = m }(mail)
+	mail = maildirMailer{dir: dir, from: "digests@example.com"}
+
+	a := roaKey{ASN: 64500, Prefix: netip.MustParsePrefix("192.0.2.0/24"), MaxLen: 24, TA: "arin"}
+	b := roaKey{ASN: 64500, Prefix: netip.MustParsePrefix("198.51.100.0/24"), MaxLen: 24, TA: "arin"}
+	other := roaKey{ASN: 64501, Prefix: netip.MustParsePrefix("203.0.113.0/24"), MaxLen: 24, TA: "arin"}
+
+	// 2024-01-01 is a Monday
+	monday := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	idx := newROAIndex()
+	runs := []struct {
+		at   time.Time
+		keys []roaKey
+	}{
+		{monday.Add(time.Hour), []roaKey{a, other}},
+		{monday.Add(12 * time.Hour), []roaKey{a, b, other}},
+		// after midnight, a going is in tomorrow's digest
+		{monday.Add(25 * time.Hour), []roaKey{b, other}},
+	}
+
+	subs := []*subscriberRow{
+		{ID: "daily", Email: "daily@example.com", ASNs: []int64{64500}, Frequency: "daily", Created: monday},
+		{ID: "weekly", Email: "weekly@example.com", ASNs: []int64{64500}, Frequency: "weekly", Created: monday},
+		{ID: "quiet", Email: "quiet@example.com", ASNs: []int64{64999}, Frequency: "daily", Created: monday},
+	}
+	sent := make(map[string]time.Time)
+	record := func(ctx context.Context, s *subscriberRow, to time.Time) error {
+		sent[s.ID] = to
+		return nil
+	}
+
+	for i, r := range runs {
+		if err := idx.addRun(r.at, r.keys); err != nil {
+			t.Fatal(err)
+		}
+		idx.sendDigests(context.Background(), subs, record)
+		if i == 1 && len(sent) > 0 {
+			t.Fatalf("digests sent before the day was over: %v", sent)
+		}
+	}
+
+	tuesday := monday.AddDate(0, 0, 1)
+	if !sent["daily"].Equal(tuesday) || !sent["quiet"].Equal(tuesday) {
+		t.Errorf("daily digests recorded up to %v, want %v", sent, tuesday)
+	}
+	if _, ok := sent["weekly"]; ok {
+		t.Errorf("weekly digest sent before the week was over")
+	}
+
+	mails, err := ioutil.ReadDir(filepath.Join(dir, "new"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(mails) != 1 {
+		t.Fatalf("got %v mails, want 1 (none for a digest with no changes)", len(mails))
+	}
+	msg, err := ioutil.ReadFile(filepath.Join(dir, "new", mails[0].Name()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	body := string(msg)
+	if !strings.Contains(body, "To: daily@example.com\r\n") {
+		t.Errorf("digest went to the wrong place:\n%v", body)
+	}
+	if !strings.Contains(body, "+ AS64500 198.51.100.0/24") {
+		t.Errorf("digest is missing the added ROA:\n%v", body)
+	}
+	if strings.Contains(body, "192.0.2.0/24") {
+		t.Errorf("digest has a change from after the day ended:\n%v", body)
+	}
+
+	// subscribers catch up from where they got to
+	subs[0].LastSent.Timestamp, subs[0].LastSent.Valid = tuesday, true
+	if err := idx.addRun(tuesday.Add(25*time.Hour), []roaKey{b, other}); err != nil {
+		t.Fatal(err)
+	}
+	idx.sendDigests(context.Background(), subs[:1], record)
+	mails, err = ioutil.ReadDir(filepath.Join(dir, "new"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(mails) != 2 {
+		t.Fatalf("got %v mails after the second day, want 2", len(mails))
+	}
+}
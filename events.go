@@ -0,0 +1,387 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/bigquery"
+	pb "github.com/gidoBOSSftw5731/Historical-ROA/proto"
+	"github.com/gidoBOSSftw5731/log"
+	"google.golang.org/api/iterator"
+)
+
+// kinds of event
+const (
+	// a prefix that's had ROAs gets one for an ASN it's never had before
+	eventNewOrigin = "new_origin"
+	eventAS0Added  = "as0_added"
+	// no AS0 ROA for the prefix any more
+	eventAS0Removed = "as0_removed"
+)
+
+// severities are the event severities, least first
+var severities = []string{"low", "medium", "high"}
+
+// eventRow is a row of the events table, a suspicious change spotted in an
+// update
+type eventRow struct {
+	// ID is stable, the same event always gets the same one
+	ID       string
+	Time     time.Time
+	RunID    string
+	Kind     string
+	Severity string
+	Prefix   string
+	// Asnum is the new origin, 0 for the AS0 events
+	Asnum int64
+	// PreviousASNs are the other origins the prefix had ROAs for before
+	PreviousASNs []int64
+	TAs          []string
+	Detail       string
+}
+
+// Save uses the ID as the insert ID, so retried inserts don't double up events
+func (e eventRow) Save() (map[string]bigquery.Value, string, error) {
+	// InferSchema caches, it's only worked out once
+	schema, err := bigquery.InferSchema(e)
+	if err != nil {
+		return nil, "", err
+	}
+	return (&bigquery.StructSaver{Schema: schema, Struct: e, InsertID: e.ID}).Save()
+}
+
+// eventROA is one ROA for a prefix as findEvents sees it: whether it's in
+// this update, the one before, and any update before this one
+type eventROA struct {
+	Asnum  int64
+	Ta     string
+	Cur    bool
+	Prev   bool
+	Before bool
+}
+
+// prefixEvents works out the events for a prefix from its ROAs. down are
+// TAs that had an outage in this update or the one before, ROAs coming and
+// going with them don't count for AS0.
+func prefixEvents(prefix string, roas []eventROA, down map[string]bool) []eventRow {
+	before := make(map[int64]bool)
+	cur := make(map[int64][]string)
+	var as0Cur, as0Prev, as0Real, othersCur, othersPrev bool
+	var as0TAs []string
+	for _, r := range roas {
+		if r.Asnum != 0 {
+			if r.Before {
+				before[r.Asnum] = true
+			}
+			if r.Cur {
+				cur[r.Asnum] = append(cur[r.Asnum], r.Ta)
+				othersCur = true
+			}
+			othersPrev = othersPrev || r.Prev
+			continue
+		}
+
+		as0Cur = as0Cur || r.Cur
+		as0Prev = as0Prev || r.Prev
+		if r.Cur != r.Prev && !down[strings.ToLower(r.Ta)] {
+			as0Real = true
+			as0TAs = append(as0TAs, r.Ta)
+		}
+	}
+
+	var previous []int64
+	for asn := range before {
+		previous = append(previous, asn)
+	}
+	sort.Slice(previous, func(i, j int) bool { return previous[i] < previous[j] })
+
+	var out []eventRow
+	event := func(kind, severity string, asn int64, tas []string, detail string) {
+		sort.Strings(tas)
+		out = append(out, eventRow{
+			Kind:         kind,
+			Severity:     severity,
+			Prefix:       prefix,
+			Asnum:        asn,
+			PreviousASNs: previous,
+			TAs:          tas,
+			Detail:       detail,
+		})
+	}
+
+	if len(before) > 0 {
+		for asn, tas := range cur {
+			if before[asn] {
+				continue
+			}
+			severity, detail := "medium", "the previous origins are gone, maybe a move"
+			for _, p := range previous {
+				if len(cur[p]) > 0 {
+					severity, detail = "high", fmt.Sprintf("added alongside AS%d", p)
+					break
+				}
+			}
+			event(eventNewOrigin, severity, asn, tas, detail)
+		}
+	}
+
+	switch {
+	case !as0Real:
+	case as0Cur && !as0Prev:
+		switch {
+		case othersPrev && !othersCur:
+			event(eventAS0Added, "high", 0, as0TAs, "replaced the ROAs for other origins")
+		case othersCur:
+			event(eventAS0Added, "medium", 0, as0TAs, "alongside ROAs for other origins")
+		default:
+			event(eventAS0Added, "low", 0, as0TAs, "")
+		}
+	case as0Prev && !as0Cur:
+		if othersCur {
+			event(eventAS0Removed, "low", 0, as0TAs, "the prefix still has ROAs for other origins")
+		} else {
+			event(eventAS0Removed, "medium", 0, as0TAs, "the prefix has no other ROAs now")
+		}
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Kind != out[j].Kind {
+			return out[i].Kind < out[j].Kind
+		}
+		return out[i].Asnum < out[j].Asnum
+	})
+	return out
+}
+
+// previousOutages is the TAs that were down in the last good update
+func previousOutages(ctx context.Context) ([]taOutage, error) {
+	it, err := runQuery(ctx, client.Query(`SELECT Outages FROM historical.runs
+	WHERE Status = "ok" ORDER BY Fetched DESC LIMIT 1`))
+	if err != nil {
+		return nil, err
+	}
+	var row struct{ Outages []taOutage }
+	err = it.Next(&row)
+	if err == iterator.Done {
+		return nil, nil
+	}
+	return row.Outages, err
+}
+
+// outageTAs is the TAs (lower case) that were down in run or the good update
+// before it. ROAs coming and going with them are most likely the outage
+// starting or ending rather than anything real.
+func outageTAs(ctx context.Context, run *updateRun) (map[string]bool, error) {
+	down := make(map[string]bool)
+	for _, o := range run.Outages {
+		down[strings.ToLower(o.TA)] = true
+	}
+	prevDown, err := previousOutages(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, o := range prevDown {
+		down[strings.ToLower(o.TA)] = true
+	}
+	return down, nil
+}
+
+// findEvents looks for suspicious changes between the merged update and the
+// one before it, and records them in the events table. It returns how many
+// there were. Backfills don't look, they aren't news.
+func findEvents(ctx context.Context, run *updateRun) (int, error) {
+	down, err := outageTAs(ctx, run)
+	if err != nil {
+		return 0, err
+	}
+
+	// every ROA for any prefix that changed. roas_arr keeps the length in
+	// mask, so it goes back on the prefix.
+	query := client.Query(`WITH prev AS (
+		SELECT MAX(t) AS t FROM historical.roas_arr, UNNEST(inserttimes) t WHERE t < @fetched
+	), roas AS (
+		SELECT CONCAT(prefix, '/', CAST(mask AS STRING)) AS prefix,
+			` + asnumColumn + ` AS asnum, ta,
+			@fetched IN UNNEST(inserttimes) AS cur,
+			IFNULL((SELECT t FROM prev) IN UNNEST(inserttimes), FALSE) AS prev,
+			EXISTS(SELECT 1 FROM UNNEST(inserttimes) t WHERE t < @fetched) AS before
+		FROM historical.roas_arr
+	)
+	SELECT prefix, ARRAY_AGG(STRUCT(asnum, ta, cur, prev, before)) AS roas
+	FROM roas
+	WHERE prefix IN (SELECT prefix FROM roas WHERE cur != prev)
+		AND (SELECT t FROM prev) IS NOT NULL
+	GROUP BY prefix`)
+	query.Parameters = []bigquery.QueryParameter{{Name: "fetched", Value: run.Fetched}}
+	it, err := runQuery(ctx, query)
+	if err != nil {
+		return 0, err
+	}
+
+	var events []eventRow
+	for {
+		var row struct {
+			Prefix string
+			Roas   []eventROA
+		}
+		err := it.Next(&row)
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return 0, err
+		}
+		for _, e := range prefixEvents(row.Prefix, row.Roas, down) {
+			e.ID = fmt.Sprintf("%d-%v-%v-%d", run.Fetched.Unix(), e.Kind, e.Prefix, e.Asnum)
+			e.Time = run.Fetched
+			e.RunID = run.ID
+			events = append(events, e)
+		}
+	}
+	if len(events) == 0 {
+		return 0, nil
+	}
+
+	table, err := ensureTable(ctx, "events", eventRow{})
+	if err != nil {
+		return 0, err
+	}
+	for i := 0; i < len(events); i += stagingChunk {
+		end := i + stagingChunk
+		if end > len(events) {
+			end = len(events)
+		}
+		if err := table.Inserter().Put(ctx, events[i:end]); err != nil {
+			return 0, err
+		}
+	}
+	for _, e := range events {
+		if e.Severity == "high" {
+			log.Errorf("%v for %v: AS%d (%v)", e.Kind, e.Prefix, e.Asnum, e.Detail)
+		}
+	}
+	return len(events), nil
+}
+
+// atLeast is severity and everything above it
+func atLeast(severity string) ([]string, bool) {
+	for i, s := range severities {
+		if s == severity {
+			return severities[i:], true
+		}
+	}
+	return nil, false
+}
+
+func eventResult(e eventRow) *pb.Event {
+	out := &pb.Event{
+		Id:          e.ID,
+		Unixtime:    e.Time.Unix(),
+		RFC3339Time: e.Time.Format(time.RFC3339),
+		Kind:        e.Kind,
+		Severity:    e.Severity,
+		Prefix:      e.Prefix,
+		ASN:         fmt.Sprintf("AS%d", e.Asnum),
+		Asnum:       uint32(e.Asnum),
+		Tas:         e.TAs,
+		Detail:      e.Detail,
+	}
+	for _, asn := range e.PreviousASNs {
+		out.PreviousASNs = append(out.PreviousASNs, fmt.Sprintf("AS%d", asn))
+	}
+	return out
+}
+
+// eventsAPI lists suspicious changes, newest first. severity is the least
+// severe to include, asn matches the new origin or a previous one, and limit
+// is 100 by default.
+//
+//	/api/events[?from=...][&to=...][&kind=new_origin,as0_added][&severity=medium][&asn=...][&prefix=...][&ta=...][&limit=100]
+func eventsAPI(w http.ResponseWriter, r *http.Request) {
+	from, to, ok := timeRange(w, r)
+	if !ok {
+		return
+	}
+	where := []string{"Time BETWEEN @from AND @to"}
+	params := []bigquery.QueryParameter{{Name: "from", Value: from}, {Name: "to", Value: to}}
+
+	if s := r.FormValue("kind"); s != "" {
+		where = append(where, "Kind IN UNNEST(@kinds)")
+		params = append(params, bigquery.QueryParameter{Name: "kinds", Value: strings.Split(s, ",")})
+	}
+	if s := r.FormValue("severity"); s != "" {
+		sev, ok := atLeast(s)
+		if !ok {
+			ErrorHandler(w, r, 400, "Bad severity, try low, medium or high", nil)
+			return
+		}
+		where = append(where, "Severity IN UNNEST(@severities)")
+		params = append(params, bigquery.QueryParameter{Name: "severities", Value: sev})
+	}
+	if s := r.FormValue("asn"); s != "" {
+		asn, err := parseASN(s)
+		if err != nil {
+			ErrorHandler(w, r, 400, "Bad ASN", err)
+			return
+		}
+		where = append(where, "(Asnum = @asn OR @asn IN UNNEST(PreviousASNs))")
+		params = append(params, bigquery.QueryParameter{Name: "asn", Value: int64(asn)})
+	}
+	if s := r.FormValue("prefix"); s != "" {
+		p, err := parsePrefixParam(s)
+		if err != nil {
+			ErrorHandler(w, r, 400, "Bad prefix", err)
+			return
+		}
+		where = append(where, "Prefix = @prefix")
+		params = append(params, bigquery.QueryParameter{Name: "prefix", Value: p.String()})
+	}
+	if tas := parseTAs(r.FormValue("ta")); len(tas) > 0 {
+		where = append(where, "EXISTS(SELECT 1 FROM UNNEST(TAs) ta WHERE LOWER(ta) IN UNNEST(@tas))")
+		params = append(params, bigquery.QueryParameter{Name: "tas", Value: tas})
+	}
+	limit := 100
+	if s := r.FormValue("limit"); s != "" {
+		var err error
+		limit, err = strconv.Atoi(s)
+		if err != nil || limit <= 0 {
+			ErrorHandler(w, r, 400, "Bad limit", err)
+			return
+		}
+	}
+	params = append(params, bigquery.QueryParameter{Name: "limit", Value: limit})
+
+	if _, err := ensureTable(r.Context(), "events", eventRow{}); err != nil {
+		ErrorHandler(w, r, 500, "Error with query", err)
+		return
+	}
+	query := client.Query(`SELECT * FROM historical.events WHERE ` + strings.Join(where, " AND ") + `
+	ORDER BY Time DESC, Prefix, Kind LIMIT @limit`)
+	query.Parameters = params
+	it, err := runQuery(r.Context(), query)
+	if err != nil {
+		ErrorHandler(w, r, 500, "Error with query", err)
+		return
+	}
+
+	var out pb.Events
+	for {
+		var row eventRow
+		err := it.Next(&row)
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			ErrorHandler(w, r, 500, "Error with query", err)
+			return
+		}
+		out.Events = append(out.Events, eventResult(row))
+	}
+
+	writeProto(w, &out)
+}
@@ -0,0 +1,323 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/bigquery"
+	"github.com/gidoBOSSftw5731/log"
+	"google.golang.org/api/iterator"
+)
+
+// exportSet is a snapshot being exported: the ROAs, the router keys for the
+// formats that have them, and the run they're from
+type exportSet struct {
+	roas []*roaEntry
+	keys []routerKeyParam
+	at   time.Time
+}
+
+// exportFormat writes a snapshot in a format something else can load
+type exportFormat struct {
+	contentType string
+	// keys is whether the format has router keys
+	keys  bool
+	write func(w io.Writer, s exportSet) error
+}
+
+// exportFormats are the formats /api/snapshot can give, by the format
+// parameter
+var exportFormats = map[string]exportFormat{
+	"routinator":  {"application/json", true, writeRoutinator},
+	"rpki-client": {"application/json", true, writeRPKIClient},
+	"slurm":       {"application/json", true, writeSLURM},
+	"openbgpd":    {"text/plain", false, writeOpenBGPD},
+	"bird":        {"text/plain", false, writeBIRD},
+	"bird2":       {"text/plain", false, writeBIRD2},
+	"cisco":       {"text/plain", false, writeCisco},
+	"juniper":     {"text/plain", false, writeJuniper},
+}
+
+// sortExport puts ROAs in address order, so exports are stable
+func sortExport(es []*roaEntry) {
+	sort.Slice(es, func(i, j int) bool {
+		a, b := es[i], es[j]
+		if c := a.Prefix.Addr().Compare(b.Prefix.Addr()); c != 0 {
+			return c < 0
+		}
+		if a.Prefix.Bits() != b.Prefix.Bits() {
+			return a.Prefix.Bits() < b.Prefix.Bits()
+		}
+		if a.MaxLen != b.MaxLen {
+			return a.MaxLen < b.MaxLen
+		}
+		if a.ASN != b.ASN {
+			return a.ASN < b.ASN
+		}
+		return a.TA < b.TA
+	})
+}
+
+// routerKeysAt returns the router keys that were in the update at t
+func routerKeysAt(ctx context.Context, t time.Time) ([]routerKeyParam, error) {
+	if _, err := ensureTable(ctx, "routerkeys_arr", routerKeyRow{}); err != nil {
+		return nil, err
+	}
+	query := client.Query(`SELECT asnum, ski, pubkey, ta FROM historical.routerkeys_arr
+	WHERE @at IN UNNEST(inserttimes) ORDER BY asnum, ski`)
+	query.Parameters = []bigquery.QueryParameter{{Name: "at", Value: t}}
+	it, err := runQuery(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	var keys []routerKeyParam
+	for {
+		var row routerKeyRow
+		err := it.Next(&row)
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, routerKeyParam{int64(row.Asnum), row.SKI, row.Pubkey, row.Ta})
+	}
+	return keys, nil
+}
+
+// exportKeys is the router keys for an export, with the lookup's TA filter
+// and SLURM applied
+func exportKeys(ctx context.Context, l lookup, at time.Time) ([]routerKeyParam, error) {
+	all, err := routerKeysAt(ctx, at)
+	if err != nil {
+		return nil, err
+	}
+	if l.slurm != nil {
+		all = append(all, l.slurm.assertedKeys()...)
+	}
+
+	var keys []routerKeyParam
+	for _, k := range all {
+		if l.keep != nil && !l.keep(&roaEntry{TA: k.Ta}) {
+			continue
+		}
+		if l.slurm != nil && k.Ta != slurmTA && l.slurm.filteredKey(k) {
+			continue
+		}
+		keys = append(keys, k)
+	}
+	return keys, nil
+}
+
+// writeRoutinator writes Routinator's json output, the shape
+// inputROAArr reads
+func writeRoutinator(w io.Writer, s exportSet) error {
+	type roa struct {
+		ASN       string `json:"asn"`
+		Prefix    string `json:"prefix"`
+		MaxLength int    `json:"maxLength"`
+		TA        string `json:"ta"`
+	}
+	var doc struct {
+		Metadata struct {
+			Generated     int64  `json:"generated"`
+			GeneratedTime string `json:"generatedTime"`
+		} `json:"metadata"`
+		ROAs       []roa            `json:"roas"`
+		RouterKeys []inputRouterKey `json:"routerKeys"`
+	}
+	doc.Metadata.Generated = s.at.Unix()
+	doc.Metadata.GeneratedTime = s.at.UTC().Format(time.RFC3339)
+	doc.ROAs = make([]roa, 0, len(s.roas))
+	for _, e := range s.roas {
+		doc.ROAs = append(doc.ROAs, roa{fmt.Sprintf("AS%d", e.ASN), e.Prefix.String(), e.MaxLen, e.TA})
+	}
+	doc.RouterKeys = []inputRouterKey{}
+	for _, k := range s.keys {
+		doc.RouterKeys = append(doc.RouterKeys, inputRouterKey{fmt.Sprintf("AS%d", k.Asnum), k.SKI, k.Pubkey, k.Ta})
+	}
+	return json.NewEncoder(w).Encode(doc)
+}
+
+// writeRPKIClient writes rpki-client's json output
+func writeRPKIClient(w io.Writer, s exportSet) error {
+	type roa struct {
+		ASN       uint32 `json:"asn"`
+		Prefix    string `json:"prefix"`
+		MaxLength int    `json:"maxLength"`
+		TA        string `json:"ta"`
+	}
+	type key struct {
+		ASN    int64  `json:"asn"`
+		SKI    string `json:"ski"`
+		Pubkey string `json:"pubkey"`
+		TA     string `json:"ta"`
+	}
+	var doc struct {
+		Metadata struct {
+			Buildtime string `json:"buildtime"`
+			VRPs      int    `json:"vrps"`
+		} `json:"metadata"`
+		ROAs       []roa `json:"roas"`
+		BGPsecKeys []key `json:"bgpsec_keys"`
+	}
+	doc.Metadata.Buildtime = s.at.UTC().Format(time.RFC3339)
+	doc.Metadata.VRPs = len(s.roas)
+	doc.ROAs = make([]roa, 0, len(s.roas))
+	for _, e := range s.roas {
+		doc.ROAs = append(doc.ROAs, roa{e.ASN, e.Prefix.String(), e.MaxLen, e.TA})
+	}
+	doc.BGPsecKeys = []key{}
+	for _, k := range s.keys {
+		doc.BGPsecKeys = append(doc.BGPsecKeys, key{k.Asnum, k.SKI, k.Pubkey, k.Ta})
+	}
+	return json.NewEncoder(w).Encode(doc)
+}
+
+// writeSLURM writes the snapshot as SLURM assertions, so a validator can be
+// made to serve exactly this set whatever the RPKI says
+func writeSLURM(w io.Writer, s exportSet) error {
+	var f slurmFile
+	f.Version = 1
+	f.ValidationOutputFilters.PrefixFilters = []slurmPrefix{}
+	f.ValidationOutputFilters.BgpsecFilters = []slurmBGPsec{}
+	f.LocallyAddedAssertions.PrefixAssertions = make([]slurmPrefix, 0, len(s.roas))
+	f.LocallyAddedAssertions.BgpsecAssertions = []slurmBGPsec{}
+	for _, e := range s.roas {
+		asn, maxLen := e.ASN, e.MaxLen
+		f.LocallyAddedAssertions.PrefixAssertions = append(f.LocallyAddedAssertions.PrefixAssertions, slurmPrefix{
+			Prefix:          e.Prefix.String(),
+			ASN:             &asn,
+			MaxPrefixLength: &maxLen,
+			Comment:         e.TA,
+		})
+	}
+	for _, k := range s.keys {
+		asn := uint32(k.Asnum)
+		f.LocallyAddedAssertions.BgpsecAssertions = append(f.LocallyAddedAssertions.BgpsecAssertions, slurmBGPsec{
+			ASN:             &asn,
+			SKI:             k.SKI,
+			RouterPublicKey: k.Pubkey,
+			Comment:         k.Ta,
+		})
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(f)
+}
+
+// exportHeader is a comment saying where a config snippet came from
+func exportHeader(w io.Writer, comment string, s exportSet) {
+	fmt.Fprintf(w, "%v Historical-ROA snapshot of %v, %v VRPs\n",
+		comment, s.at.UTC().Format(time.RFC3339), len(s.roas))
+}
+
+func writeOpenBGPD(w io.Writer, s exportSet) error {
+	bw := bufio.NewWriter(w)
+	exportHeader(bw, "#", s)
+	fmt.Fprintln(bw, "roa-set {")
+	for _, e := range s.roas {
+		if e.MaxLen != e.Prefix.Bits() {
+			fmt.Fprintf(bw, "\t%v maxlen %v source-as %v\n", e.Prefix, e.MaxLen, e.ASN)
+		} else {
+			fmt.Fprintf(bw, "\t%v source-as %v\n", e.Prefix, e.ASN)
+		}
+	}
+	fmt.Fprintln(bw, "}")
+	return bw.Flush()
+}
+
+// writeBIRD writes a BIRD 1.x roa table
+func writeBIRD(w io.Writer, s exportSet) error {
+	bw := bufio.NewWriter(w)
+	exportHeader(bw, "#", s)
+	fmt.Fprintln(bw, "roa table ROAS {")
+	for _, e := range s.roas {
+		fmt.Fprintf(bw, "\troa %v max %v as %v;\n", e.Prefix, e.MaxLen, e.ASN)
+	}
+	fmt.Fprintln(bw, "}")
+	return bw.Flush()
+}
+
+// writeBIRD2 writes BIRD 2 roa4 and roa6 tables filled by static protocols
+func writeBIRD2(w io.Writer, s exportSet) error {
+	bw := bufio.NewWriter(w)
+	exportHeader(bw, "#", s)
+	for _, family := range []int{4, 6} {
+		fmt.Fprintf(bw, "roa%v table ROAS%v;\n", family, family)
+	}
+	for _, family := range []int{4, 6} {
+		fmt.Fprintf(bw, "\nprotocol static {\n\troa%v { table ROAS%v; };\n", family, family)
+		for _, e := range s.roas {
+			if prefixFamily(e.Prefix) == family {
+				fmt.Fprintf(bw, "\troute %v max %v as %v;\n", e.Prefix, e.MaxLen, e.ASN)
+			}
+		}
+		fmt.Fprintln(bw, "}")
+	}
+	return bw.Flush()
+}
+
+// writeCisco writes IOS XR static ROAs, to go under router bgp
+func writeCisco(w io.Writer, s exportSet) error {
+	bw := bufio.NewWriter(w)
+	exportHeader(bw, "!", s)
+	fmt.Fprintln(bw, "! paste under router bgp <asn>")
+	for _, e := range s.roas {
+		fmt.Fprintf(bw, " rpki route %v max %v origin %v\n", e.Prefix, e.MaxLen, e.ASN)
+	}
+	return bw.Flush()
+}
+
+// writeJuniper writes Junos static validation records as set commands
+func writeJuniper(w io.Writer, s exportSet) error {
+	bw := bufio.NewWriter(w)
+	exportHeader(bw, "#", s)
+	for _, e := range s.roas {
+		base := "routing-options"
+		if prefixFamily(e.Prefix) == 6 {
+			base = "routing-options rib inet6.0"
+		}
+		fmt.Fprintf(bw, "set %v validation static record %v maximum-length %v origin-autonomous-system %v validation-state valid\n",
+			base, e.Prefix, e.MaxLen, e.ASN)
+	}
+	return bw.Flush()
+}
+
+// exportFormatNames lists the formats, for errors
+func exportFormatNames() string {
+	var names []string
+	for n := range exportFormats {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+// writeExport writes a snapshot in format f
+func writeExport(w http.ResponseWriter, r *http.Request, f exportFormat, l lookup, roas []*roaEntry, at time.Time) {
+	s := exportSet{roas: roas, at: at}
+	sortExport(s.roas)
+	if f.keys {
+		var err error
+		s.keys, err = exportKeys(r.Context(), l, at)
+		if err != nil {
+			ErrorHandler(w, r, 500, "Can't get router keys", err)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", f.contentType)
+	if err := f.write(w, s); err != nil {
+		// too late for an error page
+		log.Errorf("writing %v export: %v", r.FormValue("format"), err)
+	}
+}
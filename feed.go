@@ -0,0 +1,251 @@
+package main
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/netip"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gidoBOSSftw5731/log"
+)
+
+// feedEntries is how many changes a feed has by default
+const feedEntries = 100
+
+// roaChange is a ROA being added or removed in run
+type roaChange struct {
+	e     *roaEntry
+	added bool
+	run   int
+}
+
+// changes is when e was added and removed, from its spans. Gaps inside one
+// of gaps (bridgeable TA outages) are skipped, and so is being there in the
+// first run, since there's nothing before it to be added to. runs is how
+// many runs there are, the caller needs a read lock.
+func (e *roaEntry) changes(gaps map[string][][2]int32, runs int) []roaChange {
+	bridged := func(first, last int32) bool {
+		for _, g := range gaps[e.TA] {
+			if g[0] <= first && last <= g[1] {
+				return true
+			}
+		}
+		return false
+	}
+
+	var out []roaChange
+	for i, s := range e.spans {
+		if i == 0 || !bridged(e.spans[i-1][1]+1, s[0]-1) {
+			if s[0] > 0 {
+				out = append(out, roaChange{e, true, int(s[0])})
+			}
+		}
+		if i+1 < len(e.spans) && bridged(s[1]+1, e.spans[i+1][0]-1) {
+			continue
+		}
+		if int(s[1])+1 < runs {
+			out = append(out, roaChange{e, false, int(s[1]) + 1})
+		}
+	}
+	return out
+}
+
+// feedChanges is the latest n changes to entries, newest first
+func (idx *roaIndex) feedChanges(entries []*roaEntry, gaps map[string][][2]int32, n int) []roaChange {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	var out []roaChange
+	for _, e := range entries {
+		out = append(out, e.changes(gaps, len(idx.runs))...)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].run != out[j].run {
+			return out[i].run > out[j].run
+		}
+		if out[i].added != out[j].added {
+			return !out[i].added
+		}
+		return out[i].e.Prefix.String() < out[j].e.Prefix.String()
+	})
+	if len(out) > n {
+		out = out[:n]
+	}
+	return out
+}
+
+// asnEntries is every ROA ever for asn
+func (idx *roaIndex) asnEntries(asn uint32) []*roaEntry {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	var out []*roaEntry
+	for _, e := range idx.byKey {
+		if e.ASN == asn {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// prefixEntries is every ROA ever for p, covering it or inside it
+func (idx *roaIndex) prefixEntries(p netip.Prefix) []*roaEntry {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	var out []*roaEntry
+	add := func(n *trieNode) {
+		out = append(out, n.roas...)
+	}
+	root := idx.root(p)
+	root.covering(p, func(n *trieNode) {
+		if n.prefix != p {
+			add(n)
+		}
+	})
+	root.covered(p, add)
+	return out
+}
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Link    atomLink    `xml:"link"`
+	Author  string      `xml:"author>name"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+}
+
+type atomEntry struct {
+	ID      string `xml:"id"`
+	Title   string `xml:"title"`
+	Updated string `xml:"updated"`
+	Content string `xml:"content"`
+}
+
+// feedAuthority is the domain in feed IDs. It mustn't change with the host
+// the feed was fetched through, so it's FEED_AUTHORITY, or the App Engine
+// domain if that isn't set.
+func feedAuthority() string {
+	if a := os.Getenv("FEED_AUTHORITY"); a != "" {
+		return a
+	}
+	if p := os.Getenv("GOOGLE_CLOUD_PROJECT"); p != "" {
+		return p + ".appspot.com"
+	}
+	return "historical-roa.invalid"
+}
+
+// feedID is a tag URI (RFC 4151) for the feed or one of its entries. They
+// only depend on the ROA and the run, so they don't change when a feed is
+// rebuilt after the next update.
+func feedID(parts ...interface{}) string {
+	id := fmt.Sprintf("tag:%v,2020:historical-roa", feedAuthority())
+	for _, p := range parts {
+		id += fmt.Sprintf("/%v", p)
+	}
+	return id
+}
+
+// feed serves ROA additions and removals for an ASN or a prefix (and the
+// ROAs covering it or inside it) as Atom. TA outages short enough to bridge
+// are left out unless bridge=false, they'd only be noise, and n is how many
+// changes to list.
+//
+//	/feed/asn/AS64496.atom[?n=100][&bridge=false]
+//	/feed/prefix/192.0.2.0/24.atom[?n=100][&bridge=false]
+func feed(w http.ResponseWriter, r *http.Request) {
+	idx := indexOr503(w, r)
+	if idx == nil {
+		return
+	}
+
+	n := feedEntries
+	if s := r.FormValue("n"); s != "" {
+		var err error
+		n, err = strconv.Atoi(s)
+		if err != nil || n <= 0 {
+			ErrorHandler(w, r, 400, "Bad n", err)
+			return
+		}
+	}
+	var gaps map[string][][2]int32
+	if r.FormValue("bridge") != "false" {
+		gaps, _ = idx.bridgeable()
+	}
+
+	path := strings.TrimPrefix(r.URL.Path, "/feed/")
+	if !strings.HasSuffix(path, ".atom") {
+		ErrorHandler(w, r, 404, "Feeds end in .atom", nil)
+		return
+	}
+	path = strings.TrimSuffix(path, ".atom")
+
+	var entries []*roaEntry
+	var kind, what string
+	switch {
+	case strings.HasPrefix(path, "asn/"):
+		asn, err := parseASN(strings.TrimPrefix(path, "asn/"))
+		if err != nil {
+			ErrorHandler(w, r, 400, "Bad ASN", err)
+			return
+		}
+		kind, what = "asn", fmt.Sprintf("AS%d", asn)
+		entries = idx.asnEntries(asn)
+	case strings.HasPrefix(path, "prefix/"):
+		p, err := parsePrefixParam(strings.TrimPrefix(path, "prefix/"))
+		if err != nil {
+			ErrorHandler(w, r, 400, "Bad prefix", err)
+			return
+		}
+		kind, what = "prefix", p.String()
+		entries = idx.prefixEntries(p)
+	default:
+		ErrorHandler(w, r, 404, "No such feed, try /feed/asn/ or /feed/prefix/", nil)
+		return
+	}
+
+	changes := idx.feedChanges(entries, gaps, n)
+	updated := idx.lastRun()
+	out := atomFeed{
+		ID:      feedID(kind, what),
+		Title:   fmt.Sprintf("ROA changes for %v", what),
+		Updated: updated.Format(time.RFC3339),
+		Link:    atomLink{Href: "https://" + r.Host + r.URL.Path, Rel: "self"},
+		Author:  "Historical-ROA",
+	}
+	for _, c := range changes {
+		at := idx.runTime(c.run)
+		action := "Removed"
+		if c.added {
+			action = "Added"
+		}
+		res := entryResult(c.e)
+		out.Entries = append(out.Entries, atomEntry{
+			ID:      feedID(strings.ToLower(action), c.e.ASN, c.e.Prefix, c.e.MaxLen, c.e.TA, at.Unix()),
+			Title:   fmt.Sprintf("%v %v %v (%v)", action, res.ASN, res.Fullprefixrange, c.e.TA),
+			Updated: at.Format(time.RFC3339),
+			Content: fmt.Sprintf("%v ROA for %v %v, maxLength %v, from %v, in the update at %v.",
+				action, res.ASN, c.e.Prefix, c.e.MaxLen, c.e.TA, at.Format(time.RFC3339)),
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/atom+xml")
+	fmt.Fprint(w, xml.Header)
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(out); err != nil {
+		log.Errorln("error writing feed: ", err)
+	}
+}
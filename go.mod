@@ -1,19 +1,35 @@
 module github.com/gidoBOSSftw5731/Historical-ROA
 
-go 1.13
+go 1.21
 
 require (
-	cloud.google.com/go v0.86.0 // indirect
 	cloud.google.com/go/bigquery v1.19.0
-	github.com/davecgh/go-spew v1.1.1 // indirect
+	cloud.google.com/go/storage v1.10.0
 	github.com/gidoBOSSftw5731/Historical-ROA/proto v0.0.0-20210702005558-8adba536b954
 	github.com/gidoBOSSftw5731/log v0.0.0-20210527210830-1611311b4b64
+	github.com/ulikunitz/xz v0.5.15
+	google.golang.org/api v0.50.0
+	google.golang.org/protobuf v1.27.1
+)
+
+require (
+	cloud.google.com/go v0.86.0 // indirect
 	github.com/golang/groupcache v0.0.0-20210331224755-41bb18bfe9da // indirect
-	github.com/shomali11/util v0.0.0-20200329021417-91c54758c87b
+	github.com/golang/protobuf v1.5.2 // indirect
+	github.com/google/go-cmp v0.5.6 // indirect
+	github.com/googleapis/gax-go/v2 v2.0.5 // indirect
+	github.com/jstemmer/go-junit-report v0.9.1 // indirect
+	go.opencensus.io v0.23.0 // indirect
+	golang.org/x/lint v0.0.0-20210508222113-6edffad5e616 // indirect
+	golang.org/x/mod v0.8.0 // indirect
 	golang.org/x/net v0.17.0 // indirect
-	google.golang.org/api v0.50.0
+	golang.org/x/oauth2 v0.0.0-20210628180205-a41e5a781914 // indirect
+	golang.org/x/sys v0.13.0 // indirect
+	golang.org/x/text v0.13.0 // indirect
+	golang.org/x/tools v0.6.0 // indirect
+	google.golang.org/appengine v1.6.7 // indirect
 	google.golang.org/genproto v0.0.0-20210708141623-e76da96a951f // indirect
-	google.golang.org/protobuf v1.27.1
+	google.golang.org/grpc v1.39.0 // indirect
 )
 
 replace github.com/gidoBOSSftw5731/Historical-ROA/proto => ./proto
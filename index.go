@@ -0,0 +1,527 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+	"os"
+	"runtime"
+	"sort"
+	"sync"
+	"time"
+	"unsafe"
+
+	"cloud.google.com/go/bigquery"
+	"github.com/gidoBOSSftw5731/log"
+	"google.golang.org/api/iterator"
+)
+
+// how often the index checks whether someone else has updated roas_arr, and
+// how old it can get before it's rebuilt anyway (backfills don't show up as
+// a newer run)
+const (
+	indexCheck   = 10 * time.Minute
+	indexRebuild = 24 * time.Hour
+)
+
+// roaEntry is one ROA in the index and when it was around
+type roaEntry struct {
+	ASN    uint32
+	Prefix netip.Prefix
+	MaxLen int
+	TA     string
+	// spans are the runs the ROA was in, as [first, last] indexes into
+	// roaIndex.runs, oldest first
+	spans [][2]int32
+}
+
+const (
+	roaEntrySize = int64(unsafe.Sizeof(roaEntry{}))
+	spanSize     = int64(unsafe.Sizeof([2]int32{}))
+)
+
+// roaKey identifies a ROA
+type roaKey struct {
+	ASN    uint32
+	Prefix netip.Prefix
+	MaxLen int
+	TA     string
+}
+
+func (e *roaEntry) key() roaKey {
+	return roaKey{e.ASN, e.Prefix, e.MaxLen, e.TA}
+}
+
+// inRun says whether the ROA was in run number k
+func (e *roaEntry) inRun(k int) bool {
+	i := sort.Search(len(e.spans), func(i int) bool { return int(e.spans[i][1]) >= k })
+	return i < len(e.spans) && int(e.spans[i][0]) <= k
+}
+
+// roaIndex is every ROA we've ever seen in a trie per family, for fast
+// covering and longest prefix lookups at any point in history.
+type roaIndex struct {
+	mu sync.RWMutex
+	// runs are the times of every update, oldest first
+	runs  []time.Time
+	v4    *trieNode
+	v6    *trieNode
+	byKey map[roaKey]*roaEntry
+	// tas interns TA names, there's only a handful
+	tas    map[string]string
+	nodes  int64
+	spans  int64
+	loaded time.Time
+
+	// outages are cached from taCounts until there's another run
+	outageMu   sync.Mutex
+	outages    map[string][][2]int32
+	outageRuns int
+}
+
+var (
+	indexMu sync.RWMutex
+	// index is nil until it's been loaded, or if it's turned off
+	index *roaIndex
+)
+
+func currentIndex() *roaIndex {
+	indexMu.RLock()
+	defer indexMu.RUnlock()
+	return index
+}
+
+func newROAIndex() *roaIndex {
+	return &roaIndex{
+		v4:     newTrie(4),
+		v6:     newTrie(6),
+		byKey:  make(map[roaKey]*roaEntry),
+		tas:    make(map[string]string),
+		nodes:  2,
+		loaded: time.Now(),
+	}
+}
+
+// indexEnabled is false if INDEX is set to false, the index needs a good
+// chunk of memory and a big query to build.
+func indexEnabled() bool {
+	return os.Getenv("INDEX") != "false"
+}
+
+// runIndex loads the index and keeps it fresh until ctx is cancelled
+func runIndex(ctx context.Context) {
+	reload := func() {
+		start := time.Now()
+		idx, err := loadIndex(ctx)
+		if err != nil {
+			log.Errorln("error loading index: ", err)
+			return
+		}
+		indexMu.Lock()
+		if index != nil && index.lastRun().After(idx.lastRun()) {
+			// an update went into the old one while this loaded, so the
+			// old one's fresher
+			indexMu.Unlock()
+			log.Debugln("index reload missed an update, keeping the old one")
+			return
+		}
+		index = idx
+		indexMu.Unlock()
+		log.Debugf("index loaded in %v: %v ROAs, %v runs", time.Since(start), len(idx.byKey), len(idx.runs))
+	}
+
+	reload()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(indexCheck):
+		}
+
+		idx := currentIndex()
+		if idx == nil || time.Since(idx.loaded) > indexRebuild {
+			reload()
+			continue
+		}
+		latest, err := latestRun(ctx)
+		if err != nil {
+			log.Errorln("index can't check for updates: ", err)
+			continue
+		}
+		if latest.After(idx.lastRun()) {
+			reload()
+		}
+	}
+}
+
+// latestRun is when the newest successful update was fetched
+func latestRun(ctx context.Context) (time.Time, error) {
+	it, err := runQuery(ctx, client.Query(
+		`SELECT MAX(Fetched) FROM historical.runs WHERE Status = "ok"`))
+	if err != nil {
+		return time.Time{}, err
+	}
+	var row []bigquery.Value
+	if err := it.Next(&row); err != nil {
+		return time.Time{}, err
+	}
+	t, _ := row[0].(time.Time)
+	return t, nil
+}
+
+// loadIndex builds an index from roas_arr. Rather than pull every
+// inserttime out, BigQuery works out the spans of consecutive runs each ROA
+// was in (gaps and islands: the run number minus the ROA's own row number is
+// the same all through an unbroken span).
+func loadIndex(ctx context.Context) (*roaIndex, error) {
+	idx := newROAIndex()
+
+	var err error
+	idx.runs, err = queryUpdateTimes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(idx.runs) == 0 {
+		return idx, nil
+	}
+
+	// only the runs that were just read, an update merged since then would
+	// give spans past the end of them
+	query := client.Query(`WITH obs AS (
+		SELECT DISTINCT asn, prefix, mask, maxlen, ta, t
+		FROM historical.roas_arr, UNNEST(inserttimes) t
+		WHERE t IN UNNEST(@runs)
+	), runs AS (
+		SELECT t, ROW_NUMBER() OVER (ORDER BY t) AS n FROM (SELECT DISTINCT t FROM obs)
+	)
+	SELECT asn, prefix, mask, maxlen, ta, ARRAY_AGG(STRUCT(first, last) ORDER BY first) AS spans
+	FROM (
+		SELECT asn, prefix, mask, maxlen, ta, MIN(t) AS first, MAX(t) AS last
+		FROM (
+			SELECT o.*, r.n - ROW_NUMBER() OVER (
+				PARTITION BY asn, prefix, mask, maxlen, ta ORDER BY o.t) AS island
+			FROM obs o JOIN runs r USING (t)
+		)
+		GROUP BY asn, prefix, mask, maxlen, ta, island
+	)
+	GROUP BY asn, prefix, mask, maxlen, ta`)
+	query.Parameters = []bigquery.QueryParameter{{Name: "runs", Value: idx.runs}}
+	it, err := runQuery(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		var row struct {
+			Asn    string
+			Prefix string
+			Mask   int
+			Maxlen int
+			Ta     string
+			Spans  []struct{ First, Last time.Time }
+		}
+		err := it.Next(&row)
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		addr, err := netip.ParseAddr(row.Prefix)
+		if err != nil {
+			log.Traceln("index skipping bad prefix ", row.Prefix)
+			continue
+		}
+		asn, err := parseASN(row.Asn)
+		if err != nil {
+			log.Traceln("index skipping bad asn ", row.Asn)
+			continue
+		}
+
+		e := idx.entry(roaKey{asn, netip.PrefixFrom(addr, row.Mask).Masked(), row.Maxlen, row.Ta})
+		for _, s := range row.Spans {
+			e.spans = append(e.spans, [2]int32{idx.runNumber(s.First), idx.runNumber(s.Last)})
+		}
+		idx.spans += int64(len(row.Spans))
+	}
+
+	return idx, nil
+}
+
+// queryUpdateTimes is the time of every update in roas_arr, oldest first
+func queryUpdateTimes(ctx context.Context) ([]time.Time, error) {
+	it, err := runQuery(ctx, client.Query(
+		`SELECT DISTINCT t FROM historical.roas_arr, UNNEST(inserttimes) t ORDER BY t`))
+	if err != nil {
+		return nil, err
+	}
+	var runs []time.Time
+	for {
+		var row []bigquery.Value
+		err := it.Next(&row)
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		runs = append(runs, row[0].(time.Time))
+	}
+	return runs, nil
+}
+
+// updateTimes is the time of every update from the index, nil if it isn't
+// loaded. Working them out without it means reading every row.
+func updateTimes() []time.Time {
+	idx := currentIndex()
+	if idx == nil {
+		return nil
+	}
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	return idx.runs[:len(idx.runs):len(idx.runs)]
+}
+
+// runNumber is the index in runs of the run at t
+func (idx *roaIndex) runNumber(t time.Time) int32 {
+	return int32(sort.Search(len(idx.runs), func(i int) bool { return !idx.runs[i].Before(t) }))
+}
+
+// runAt is the number of the latest run at or before t, or -1 if there
+// wasn't one
+func (idx *roaIndex) runAt(t time.Time) int {
+	return sort.Search(len(idx.runs), func(i int) bool { return idx.runs[i].After(t) }) - 1
+}
+
+// runTime is when run k was
+func (idx *roaIndex) runTime(k int) time.Time {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	return idx.runs[k]
+}
+
+func (idx *roaIndex) lastRun() time.Time {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	if len(idx.runs) == 0 {
+		return time.Time{}
+	}
+	return idx.runs[len(idx.runs)-1]
+}
+
+// entry returns the entry for k, adding it if it's new.
+// The caller must hold the write lock if the index is in use.
+func (idx *roaIndex) entry(k roaKey) *roaEntry {
+	if e, ok := idx.byKey[k]; ok {
+		return e
+	}
+
+	ta, ok := idx.tas[k.TA]
+	if !ok {
+		idx.tas[k.TA] = k.TA
+		ta = k.TA
+	}
+	e := &roaEntry{ASN: k.ASN, Prefix: k.Prefix, MaxLen: k.MaxLen, TA: ta}
+	idx.byKey[k] = e
+
+	root := idx.v6
+	if k.Prefix.Addr().Is4() {
+		root = idx.v4
+	}
+	idx.nodes += int64(root.insert(k.Prefix, e))
+	return e
+}
+
+// addRun records a freshly merged update. Runs have to be added in order,
+// anything older than the latest run means the index needs rebuilding.
+func (idx *roaIndex) addRun(at time.Time, keys []roaKey) error {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	if len(idx.runs) > 0 && !at.After(idx.runs[len(idx.runs)-1]) {
+		return fmt.Errorf("run at %v is older than the index's latest", at)
+	}
+	idx.runs = append(idx.runs, at)
+	k := int32(len(idx.runs) - 1)
+
+	for _, key := range keys {
+		e := idx.entry(key)
+		n := len(e.spans)
+		if n > 0 && e.spans[n-1][1] == k {
+			// listed twice in the same run
+			continue
+		}
+		if n > 0 && e.spans[n-1][1] == k-1 {
+			e.spans[n-1][1] = k
+			continue
+		}
+		e.spans = append(e.spans, [2]int32{k, k})
+		idx.spans++
+	}
+	return nil
+}
+
+// lookup is which ROAs a query wants: the ones in a run, maybe only some of
+// them, maybe with TA outages bridged over
+type lookup struct {
+	run  int
+	keep func(*roaEntry) bool
+	// gaps are TA outages to bridge, by TA, and runs is how many runs there
+	// were when they were found
+	gaps map[string][][2]int32
+	runs int
+	// slurm is applied on top, if there is one
+	slurm *slurmFile
+}
+
+// has says whether the lookup wants e
+func (l lookup) has(e *roaEntry) bool {
+	if l.keep != nil && !l.keep(e) {
+		return false
+	}
+	if l.slurm != nil && l.slurm.filtered(e) {
+		return false
+	}
+	if e.inRun(l.run) {
+		return true
+	}
+
+	// during a bridged outage, ROAs there either side of it count. One
+	// that's still going isn't bridged yet, like in changes.
+	for _, g := range l.gaps[e.TA] {
+		first, last := int(g[0]), int(g[1])
+		if l.run < first || l.run > last {
+			continue
+		}
+		return e.inRun(first-1) && last+1 < l.runs && e.inRun(last+1)
+	}
+	return false
+}
+
+// asserted are the ROAs the lookup's SLURM adds, that it wants
+func (l lookup) asserted() []*roaEntry {
+	if l.slurm == nil {
+		return nil
+	}
+	var out []*roaEntry
+	for _, e := range l.slurm.asserted {
+		if l.keep == nil || l.keep(e) {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// lookup makes a lookup for run k, bridging TA outages if asked to
+func (idx *roaIndex) lookup(k int, keep func(*roaEntry) bool, bridge bool) lookup {
+	l := lookup{run: k, keep: keep}
+	if bridge {
+		l.gaps, l.runs = idx.bridgeable()
+	}
+	return l
+}
+
+// covering returns the ROAs covering p, most specific first
+func (idx *roaIndex) covering(p netip.Prefix, l lookup) []*roaEntry {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	var out []*roaEntry
+	idx.root(p).covering(p, func(n *trieNode) {
+		for _, e := range n.roas {
+			if l.has(e) {
+				out = append(out, e)
+			}
+		}
+	})
+
+	// reverse, the trie gives them least specific first
+	for i, j := 0, len(out)-1; i < j; i, j = i+1, j-1 {
+		out[i], out[j] = out[j], out[i]
+	}
+
+	asserted := false
+	for _, e := range l.asserted() {
+		if e.Prefix.Bits() <= p.Bits() && e.Prefix.Contains(p.Addr()) {
+			out = append(out, e)
+			asserted = true
+		}
+	}
+	if asserted {
+		sort.SliceStable(out, func(i, j int) bool { return out[i].Prefix.Bits() > out[j].Prefix.Bits() })
+	}
+	return out
+}
+
+// snapshot returns every ROA the lookup wants
+func (idx *roaIndex) snapshot(l lookup) []*roaEntry {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	var out []*roaEntry
+	for _, root := range []*trieNode{idx.v4, idx.v6} {
+		root.walk(func(n *trieNode) {
+			for _, e := range n.roas {
+				if l.has(e) {
+					out = append(out, e)
+				}
+			}
+		})
+	}
+	return append(out, l.asserted()...)
+}
+
+func (idx *roaIndex) root(p netip.Prefix) *trieNode {
+	if p.Addr().Is4() {
+		return idx.v4
+	}
+	return idx.v6
+}
+
+// validity does RFC 6811 origin validation of a route for p from asn against
+// the lookup, returning the state and the covering ROAs that did and didn't
+// match
+func (idx *roaIndex) validity(p netip.Prefix, asn uint32, l lookup) (string, []*roaEntry, []*roaEntry) {
+	var matched, unmatched []*roaEntry
+	for _, e := range idx.covering(p, l) {
+		// AS0 ROAs never match anything (RFC 7607)
+		if e.ASN == asn && asn != 0 && p.Bits() <= e.MaxLen {
+			matched = append(matched, e)
+		} else {
+			unmatched = append(unmatched, e)
+		}
+	}
+
+	switch {
+	case len(matched) > 0:
+		return "valid", matched, unmatched
+	case len(unmatched) > 0:
+		return "invalid", matched, unmatched
+	}
+	return "notfound", matched, unmatched
+}
+
+// size estimates how much memory the index takes up
+func (idx *roaIndex) size() int64 {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	// a pointer in the trie and one in the map for every entry
+	perEntry := roaEntrySize + 2*int64(unsafe.Sizeof(&roaEntry{})) + int64(unsafe.Sizeof(roaKey{}))
+	return idx.nodes*trieNodeSize + int64(len(idx.byKey))*perEntry +
+		idx.spans*spanSize + int64(len(idx.runs))*int64(unsafe.Sizeof(time.Time{}))
+}
+
+// heapBytes is how much heap the whole process is using
+func heapBytes() int64 {
+	var m runtime.MemStats
+	runtime.ReadMemStats(&m)
+	return int64(m.HeapAlloc)
+}
+
+// storedKey is the index key for a staged ROA
+func storedKey(s storedROA) roaKey {
+	addr, _ := netip.ParseAddr(s.Prefix)
+	return roaKey{s.AsnNum, netip.PrefixFrom(addr, s.Subnet), s.MaxLength, s.Ta}
+}
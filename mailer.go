@@ -0,0 +1,143 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"mime"
+	"net"
+	netmail "net/mail"
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// mailer sends email. SMTP is what we use for real, the maildir sink is for
+// testing without sending anything.
+type mailer interface {
+	// Send sends a plain text email
+	Send(ctx context.Context, to, subject, body string) error
+}
+
+// mail is nil if email isn't configured
+var mail mailer
+
+// mailerFromEnv sets up the mailer, returning nil if neither MAIL_SMTP nor
+// MAIL_DIR is set.
+//
+//	MAIL_FROM      the From address, needed for either
+//	MAIL_SMTP      SMTP server as host:port
+//	MAIL_USER      SMTP username, if it needs one
+//	MAIL_PASSWORD  SMTP password
+//	MAIL_DIR       maildir to write the mail to instead of sending it
+func mailerFromEnv() (mailer, error) {
+	from := os.Getenv("MAIL_FROM")
+	server, dir := os.Getenv("MAIL_SMTP"), os.Getenv("MAIL_DIR")
+	switch {
+	case server == "" && dir == "":
+		return nil, nil
+	case server != "" && dir != "":
+		return nil, errors.New("MAIL_SMTP and MAIL_DIR can't both be set")
+	case from == "":
+		return nil, errors.New("MAIL_FROM needs setting to send mail")
+	}
+	addr, err := netmail.ParseAddress(from)
+	if err != nil {
+		return nil, fmt.Errorf("bad MAIL_FROM: %v", err)
+	}
+	from = addr.String()
+
+	if dir != "" {
+		for _, sub := range []string{"tmp", "new", "cur"} {
+			if err := os.MkdirAll(filepath.Join(dir, sub), 0755); err != nil {
+				return nil, err
+			}
+		}
+		return maildirMailer{dir, from}, nil
+	}
+
+	host, _, err := net.SplitHostPort(server)
+	if err != nil {
+		return nil, fmt.Errorf("bad MAIL_SMTP: %v", err)
+	}
+	m := smtpMailer{addr: server, from: from, sender: addr.Address}
+	if user := os.Getenv("MAIL_USER"); user != "" {
+		m.auth = smtp.PlainAuth("", user, os.Getenv("MAIL_PASSWORD"), host)
+	}
+	return m, nil
+}
+
+// message makes an RFC 5322 message
+func message(from, to, subject, body string) ([]byte, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return nil, err
+	}
+	domain := "historical-roa"
+	if at := strings.LastIndexByte(from, '@'); at != -1 {
+		domain = strings.TrimSuffix(from[at+1:], ">")
+	}
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "From: %v\r\n", from)
+	fmt.Fprintf(&b, "To: %v\r\n", to)
+	fmt.Fprintf(&b, "Subject: %v\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&b, "Date: %v\r\n", time.Now().Format(time.RFC1123Z))
+	fmt.Fprintf(&b, "Message-ID: <%v@%v>\r\n", hex.EncodeToString(id), domain)
+	fmt.Fprint(&b, "MIME-Version: 1.0\r\n")
+	fmt.Fprint(&b, "Content-Type: text/plain; charset=utf-8\r\n")
+	fmt.Fprint(&b, "\r\n")
+	b.Write(bytes.ReplaceAll([]byte(body), []byte("\n"), []byte("\r\n")))
+	return b.Bytes(), nil
+}
+
+type smtpMailer struct {
+	addr string
+	from string
+	// sender is the bare address in from, for the envelope
+	sender string
+	auth   smtp.Auth
+}
+
+func (m smtpMailer) Send(ctx context.Context, to, subject, body string) error {
+	msg, err := message(m.from, to, subject, body)
+	if err != nil {
+		return err
+	}
+	return smtp.SendMail(m.addr, m.auth, m.sender, []string{to}, msg)
+}
+
+// maildirMailer delivers into a maildir, so tests can read what would have
+// been sent
+type maildirMailer struct {
+	dir  string
+	from string
+}
+
+func (m maildirMailer) Send(ctx context.Context, to, subject, body string) error {
+	msg, err := message(m.from, to, subject, body)
+	if err != nil {
+		return err
+	}
+
+	// written to tmp and moved to new, like every maildir writer
+	tmp, err := ioutil.TempFile(filepath.Join(m.dir, "tmp"), fmt.Sprintf("%d.", time.Now().UnixNano()))
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(msg); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), filepath.Join(m.dir, "new", filepath.Base(tmp.Name())))
+}
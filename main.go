@@ -1,21 +1,22 @@
 package main
 
 import (
-	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"html/template"
+	"io"
 	"io/ioutil"
-	"net"
 	"net/http"
+	"net/netip"
 	"os"
-	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
 
-	"github.com/shomali11/util/xhashes"
-
 	"cloud.google.com/go/bigquery"
 	pb "github.com/gidoBOSSftw5731/Historical-ROA/proto"
 	"github.com/gidoBOSSftw5731/log"
@@ -32,10 +33,15 @@ type inputROA struct {
 	MaxLength int    `json:"maxLength"`
 	Ta        string `json:"ta"`
 	ParseCIDR string
+	// Source is where the VRP came from, if the validator says
+	Source []inputSource `json:"source,omitempty"`
 }
 
 type inputROAArr struct {
-	Roas []inputROA `json:"roas"`
+	Metadata   inputMetadata    `json:"metadata"`
+	Roas       []inputROA       `json:"roas"`
+	ASPAs      []inputASPA      `json:"aspas,omitempty"`
+	RouterKeys []inputRouterKey `json:"routerKeys,omitempty"`
 }
 
 // storedROAs is what we store, we simply trim the subnet
@@ -46,15 +52,36 @@ type storedROA struct {
 	MaxLength int    `json:"maxLength"`
 	Ta        string `json:"ta"`
 	Subnet    int
+	AsnNum    uint32
+	// Family is 4 or 6, StartAddr and EndAddr are the first and last
+	// address in the prefix, see prefixRange
+	Family    int
+	StartAddr []byte
+	EndAddr   []byte
+	// provenance, if the validator gave it, see inputSource
+	URI       string
+	SKI       string
+	NotBefore bigquery.NullTimestamp
+	NotAfter  bigquery.NullTimestamp
 }
 
-type storedROAWithTime struct {
-	Asn       string `json:"asn"`
-	Prefix    string `json:"prefix"`
-	MaxLength int    `json:"maxLength"`
-	Ta        string `json:"ta"`
-	Subnet    int
-	Times     []time.Time
+// roaRow is a row of roas_arr. inserttimes are when we fetched each set the
+// ROA was in and generatedtimes are when the validator made that set, in the
+// same order.
+type roaRow struct {
+	Asn            string      `bigquery:"asn"`
+	Prefix         string      `bigquery:"prefix"`
+	Maxlen         int         `bigquery:"maxlen"`
+	Ta             string      `bigquery:"ta"`
+	Mask           int         `bigquery:"mask"`
+	InsertTimes    []time.Time `bigquery:"inserttimes"`
+	GeneratedTimes []time.Time `bigquery:"generatedtimes"`
+	// Asnum is Asn as a number, for queries
+	Asnum uint32 `bigquery:"asnum"`
+	// the prefix as a range of addresses, for containment queries
+	Family    int    `bigquery:"family"`
+	StartAddr []byte `bigquery:"startaddr"`
+	EndAddr   []byte `bigquery:"endaddr"`
 }
 
 // google cloud credentials file
@@ -74,6 +101,9 @@ type Creds struct {
 var (
 	client *bigquery.Client
 	gcreds Creds
+	// updating is 1 while an update is running, so cron, the scheduler and
+	// manual hits on /update never merge the same snapshot twice
+	updating int32
 )
 
 const (
@@ -114,9 +144,72 @@ func main() {
 
 	client.Location = projectLocation
 
-	http.HandleFunc("/update", pullToDB)
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "backfill":
+			err = backfillCmd(os.Args[2:])
+		case "migrate":
+			err = migrateCmd(os.Args[2:])
+		default:
+			err = fmt.Errorf("unknown command %q", os.Args[1])
+		}
+		if err != nil {
+			log.Fatalln(err)
+		}
+		return
+	}
+
+	archive, err = archiveFromEnv()
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	localRepo, err = repoFromEnv()
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	mail, err = mailerFromEnv()
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	sched, err := schedulerFromEnv()
+	if err != nil {
+		log.Fatalln(err)
+	}
+	if sched != nil {
+		updateInterval = sched.interval
+		go sched.run(context.Background())
+	}
+
+	if indexEnabled() {
+		go runIndex(context.Background())
+	} else if mail != nil {
+		log.Fatalln("digests are made from the index, INDEX can't be false with email set up")
+	}
+
+	auth := updateAuthFromEnv()
+
+	http.HandleFunc("/update", auth.require(pullToDB))
 	http.HandleFunc("/", mainPage)
 	http.HandleFunc("/hsts", hsts)
+	http.HandleFunc("/api/covering", coveringAPI)
+	http.HandleFunc("/api/validity", validityAPI)
+	http.HandleFunc("/api/snapshot", snapshotAPI)
+	http.HandleFunc("/api/tas", tasAPI)
+	http.HandleFunc("/api/maxlen", maxLenAPI)
+	http.HandleFunc("/api/stats/churn", churnAPI)
+	http.HandleFunc("/api/events", eventsAPI)
+	http.HandleFunc("/api/stream", streamAPI)
+	http.HandleFunc("/api/watchlists", auth.require(watchlistAPI))
+	http.HandleFunc("/api/subscribers", auth.require(subscriberAPI))
+	http.HandleFunc("/api/aspa", aspaAPI)
+	http.HandleFunc("/api/routerkeys", routerKeysAPI)
+	http.HandleFunc("/api/slurm", slurmAPI)
+	http.HandleFunc("/api/slurm/upload", auth.require(slurmUpload))
+	http.HandleFunc("/feed/", feed)
+	http.HandleFunc("/debug/index", indexDebug)
 	//http.HandleFunc("/aaaaaaaaaaaaaaaa", movefromoldtonew.Main)
 	log.Fatal(http.ListenAndServe(fmt.Sprintf(":%s", port), nil))
 }
@@ -151,55 +244,72 @@ func mainPage(w http.ResponseWriter, r *http.Request) {
 		ParseCIDR: r.FormValue("parsecidr"),
 	}
 
-	if input.ParseCIDR != "" {
-		_, n, err := net.ParseCIDR(input.Prefix)
+	var prefix netip.Prefix
+	if input.Prefix != "" {
+		prefix, err = netip.ParsePrefix(strings.TrimSpace(input.Prefix))
 		if err != nil {
-			tmpl.Execute(w, nil)
+			ErrorHandler(w, r, 400, "Bad prefix", err)
 			return
 		}
-		input.Prefix = n.String()
+		if input.ParseCIDR != "" {
+			prefix = prefix.Masked()
+		}
 	}
 
-	inputStore := convInToStored(input)
+	// by default the times are when we fetched the ROAs, this gives when the
+	// validator generated them instead
+	useGenerated := r.FormValue("timesource") == "generated"
+
+	log.Traceln(input)
+
+	var where []string
+	var params []bigquery.QueryParameter
+
+	if input.Asn != "" {
+		lo, hi, err := parseASNRange(input.Asn)
+		if err != nil {
+			ErrorHandler(w, r, 400, "Bad ASN", err)
+			return
+		}
+		where = append(where, asnumColumn+" BETWEEN @asnlo AND @asnhi")
+		params = append(params,
+			bigquery.QueryParameter{Name: "asnlo", Value: int64(lo)},
+			bigquery.QueryParameter{Name: "asnhi", Value: int64(hi)})
+	}
 
-	var hasASN, hasPrefix bool
-	if inputStore.Asn != "" {
-		hasASN = true
+	if prefix.IsValid() {
+		cond, prefixParams, err := prefixCondition(r.FormValue("match"), prefix)
+		if err != nil {
+			ErrorHandler(w, r, 400, "Bad match", err)
+			return
+		}
+		where = append(where, cond)
+		params = append(params, prefixParams...)
 	}
 
-	if inputStore.Prefix != "" && inputStore.Subnet != 0 {
-		hasPrefix = true
+	if len(where) == 0 {
+		tmpl.Execute(w, nil)
+		return
 	}
 
-	log.Traceln(input)
+	// only narrows things down, a whole TA is too much for one page
+	if tas := parseTAs(r.FormValue("ta")); len(tas) > 0 {
+		cond, param := taCondition(tas)
+		where = append(where, cond)
+		params = append(params, param)
+	}
 
-	var query *bigquery.Query
-	switch {
-	case hasASN && !hasPrefix:
-		query = client.Query(`SELECT asn, prefix, mask, maxlen, ta, inserttimes FROM historical-roas.historical.roas_arr
-		WHERE asn = @asn`)
-
-	case !hasASN && hasPrefix:
-		query = client.Query(`SELECT asn, prefix, mask, maxlen, ta, inserttimes FROM historical-roas.historical.roas_arr
-		WHERE prefix = @prefix AND mask = @mask`)
-	case hasASN && hasPrefix:
-		query = client.Query(`SELECT asn, prefix, mask, maxlen, ta, inserttimes FROM historical-roas.historical.roas_arr
-		WHERE asn = @asn AND prefix = @prefix AND mask = @mask`)
-	}
-	query.Parameters = []bigquery.QueryParameter{
-		{
-			Name:  "asn",
-			Value: inputStore.Asn,
-		},
-		{
-			Name:  "prefix",
-			Value: inputStore.Prefix,
-		},
-		{
-			Name:  "mask",
-			Value: inputStore.Subnet,
-		},
+	if err := ensureProvenance(ctx); err != nil {
+		ErrorHandler(w, r, 500, "Error with query", err)
+		return
 	}
+
+	query := client.Query(`SELECT asn, prefix, mask, maxlen, ta, inserttimes, generatedtimes,
+		` + asnumColumn + ` AS asnum,
+		` + provenanceColumn + `
+	FROM historical-roas.historical.roas_arr r
+	WHERE ` + strings.Join(where, " AND "))
+	query.Parameters = params
 	job, err := query.Run(ctx)
 	if err != nil {
 		ErrorHandler(w, r, 500, "Error with query", err)
@@ -217,6 +327,10 @@ func mainPage(w http.ResponseWriter, r *http.Request) {
 		ErrorHandler(w, r, 500, "Error with query", err)
 		return
 	}
+	// for the lifespans, they're left out without the index
+	runs := updateTimes()
+	now := time.Now()
+
 	var resultsarr pb.ResultArr
 	for {
 		var row []bigquery.Value
@@ -228,12 +342,19 @@ func mainPage(w http.ResponseWriter, r *http.Request) {
 			ErrorHandler(w, r, 500, "Error with query", err)
 			continue
 		}
-		var intime []time.Time
+		var intime, gentime []time.Time
 		var buf = row[5].([]bigquery.Value)
 
 		for _, t := range buf {
 			intime = append(intime, t.(time.Time))
 		}
+		for _, t := range row[6].([]bigquery.Value) {
+			gentime = append(gentime, t.(time.Time))
+		}
+		// rows nobody has merged into since generatedtimes was added
+		if len(gentime) != len(intime) {
+			gentime = intime
+		}
 
 		var results = pb.ResultsFromDB{
 			ASN:    row[0].(string),       // this
@@ -242,65 +363,97 @@ func mainPage(w http.ResponseWriter, r *http.Request) {
 			Maxlen: int32(row[3].(int64)), // I hate you,
 			Ta:     row[4].(string),       // Google
 		}
+		// NULL if asn doesn't parse
+		if asnum, ok := row[7].(int64); ok {
+			results.Asnum = uint32(asnum)
+		}
+
+		if runs != nil {
+			results.Lifespan = lifespan(timeSpans(intime, runs), runs, now)
+		}
+
+		for _, i := range gentime {
+			results.Generatedunixtimearr = append(results.Generatedunixtimearr, i.Unix())
+			results.RFC3339Generatedtimearr = append(results.RFC3339Generatedtimearr, i.Format(time.RFC3339))
+		}
+		if useGenerated {
+			intime = gentime
+		}
 
 		for _, i := range intime {
 			results.Unixtimearr = append(results.Unixtimearr, (i.Unix()))
 			results.RFC3339Timearr = append(results.RFC3339Timearr, i.Format(time.RFC3339))
 		}
 
-		results.Fullprefix = fmt.Sprintf("%v/%v", results.Prefix, results.Mask)
-		switch {
-		case results.Maxlen != results.Mask:
-			results.Fullprefixrange = fmt.Sprintf("%v/%v => %v",
-				results.Prefix, results.Mask, results.Maxlen)
-		case results.Maxlen == results.Mask:
-			results.Fullprefixrange = fmt.Sprintf("%v/%v", results.Prefix, results.Mask)
-		}
+		results.Provenance = provenanceResults(row[8])
+		setFullprefix(&results)
 
 		resultsarr.Results = append(resultsarr.Results, &results)
 	}
 	fmt.Fprintln(w, protojson.Format(&resultsarr))
 }
 
-// convert input data into stored data
-func convInToStored(i inputROA) storedROA {
-	// shut up I know its not correct terminology
-	ipandmask := strings.Split(i.Prefix, "/")
+// errors returned by beginUpdate when an update shouldn't run right now
+var (
+	errUpdateRunning   = errors.New("update already running")
+	errRecentlyUpdated = errors.New("already done")
+)
+
+// recentWindow is how long after a merge further updates are refused
+const recentWindow = 50 * time.Minute
 
-	var mask int
-	// probably doesnt need error checking
-	if len(ipandmask) == 2 {
-		mask, _ = strconv.Atoi(ipandmask[1])
-	}
+// updateInterval is how often updates run, hourly from cron.yaml unless the
+// scheduler's set up
+var updateInterval = time.Hour
 
-	return storedROA{
-		Asn:       i.Asn,
-		Prefix:    ipandmask[0],
-		MaxLength: i.MaxLength,
-		Ta:        i.Ta,
-		Subnet:    mask,
+// lastUpdate returns the last time the dataset was modified
+func lastUpdate(ctx context.Context) (time.Time, error) {
+	query := client.Query("SELECT LAST_MODIFIED_TIME FROM INFORMATION_SCHEMA.SCHEMATA")
+	row, err := query.Read(ctx)
+	if err != nil {
+		return time.Time{}, err
 	}
+	var time_row []bigquery.Value
+	err = row.Next(&time_row)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time_row[0].(time.Time), nil
 }
 
-func pullToDB(w http.ResponseWriter, r *http.Request) {
+// beginUpdate takes the update lock and makes sure nothing was merged
+// recently. On success the returned func must be called to release the lock.
+func beginUpdate(ctx context.Context) (func(), error) {
+	if !atomic.CompareAndSwapInt32(&updating, 0, 1) {
+		return nil, errUpdateRunning
+	}
+	release := func() { atomic.StoreInt32(&updating, 0) }
 
-	// see if there has been an update within 55 mins
-	query := client.Query("SELECT LAST_MODIFIED_TIME FROM INFORMATION_SCHEMA.SCHEMATA")
-	row, _ := query.Read(context.Background())
-	var time_row []bigquery.Value
-	err := row.Next(&time_row)
+	// see if there has been an update within 50 mins
+	lastIn, err := lastUpdate(ctx)
 	switch err {
 	case nil:
-		lastIn := time_row[0].(time.Time)
-		if lastIn.Add(50 * time.Minute).After(time.Now()) {
+		if lastIn.Add(recentWindow).After(time.Now()) {
 			log.Traceln("Record added in last 50 mins")
-			ErrorHandler(w, r, 401, "already done", nil)
-			return
+			release()
+			return nil, errRecentlyUpdated
 		}
 	default:
-		//ErrorHandler(w, r, 500, "Cant get last edit time", err)
-		log.Errorln("Cant get last edit time")
+		log.Errorln("Cant get last edit time: ", err)
+	}
+
+	return release, nil
+}
+
+func pullToDB(w http.ResponseWriter, r *http.Request) {
+	ctx := context.Background()
+
+	release, err := beginUpdate(ctx)
+	if err != nil {
+		ErrorHandler(w, r, 401, err.Error(), nil)
+		return
 	}
+	defer release()
 
 	w.WriteHeader(200)
 	fmt.Fprintln(w, "ok")
@@ -308,180 +461,151 @@ func pullToDB(w http.ResponseWriter, r *http.Request) {
 	hj, _ := w.(http.Hijacker)
 	conn, _, _ := hj.Hijack()
 	conn.Close()
-	log.Debugln("starting update")
 
-	origIn, err := downloadRARC()
-	if err != nil {
-		ErrorHandler(w, r, 500, "Error parsing JSON", err)
-		return
+	if err := updateROAs(ctx); err != nil {
+		log.Errorln("update failed: ", err)
 	}
-	ctx := context.Background()
+}
 
-	//inserter := client.Dataset("historical").Table("roas_arr").Inserter()
+// updateROAs downloads the current ROAs and merges them into roas_arr.
+// The caller must hold the update lock (see beginUpdate).
+func updateROAs(ctx context.Context) (err error) {
+	log.Debugln("starting update")
 
-	schema, err := bigquery.InferSchema(storedROAWithTime{})
-	if err != nil {
-		ErrorHandler(w, r, 500, "failed to infer schema", err)
-		return
+	source := roaURL
+	if localRepo != nil {
+		source = localRepo.String()
 	}
+	run := newUpdateRun(source)
+	defer func() { run.finish(ctx, err) }()
 
-	schema = schema.Relax()
-
-	//query and dump to map
-	var stored = make(map[string]struct{})
-
-	currentQuery := client.Query(`SELECT asn, ta, prefix, mask, maxlen FROM historical-roas.historical.roas_arr`)
-	job, err := currentQuery.Run(ctx)
+	staging, err := newStagingWriter(ctx, "buf", run.ID)
 	if err != nil {
-		ErrorHandler(w, r, 500, "Error with query", err)
-		return
+		return err
 	}
 
-	status, _ := job.Wait(ctx)
-	if err := status.Err(); err != nil {
-		ErrorHandler(w, r, 500, "Error with query", err)
-		return
+	// count the ROAs per TA, and remember what's in this run for the index
+	var keys []roaKey
+	taCounts := make(map[string]int)
+	withIndex := indexEnabled()
+	staging.onRow = func(s storedROA) {
+		taCounts[s.Ta]++
+		if withIndex {
+			keys = append(keys, storedKey(s))
+		}
 	}
 
-	it, err := job.Read(ctx)
+	var doc inputROAArr
+	if localRepo != nil {
+		doc, err = localRepo.load(ctx, run, staging)
+	} else {
+		doc, err = downloadRARC(ctx, run, staging)
+	}
 	if err != nil {
-		ErrorHandler(w, r, 500, "Error with query", err)
-		return
+		staging.Close()
+		return fmt.Errorf("error getting ROAs: %v", err)
 	}
-
-	for {
-		var row []bigquery.Value
-		err := it.Next(&row)
-		if err == iterator.Done {
-			break
-		}
-		if err != nil {
-			ErrorHandler(w, r, 500, "Error with query", err)
-			continue
-		}
-
-		stored[xhashes.MD5(fmt.Sprint(pb.ResultsFromDB{
-			ASN:    row[0].(string),
-			Ta:     row[1].(string),
-			Prefix: row[2].(string),
-			Mask:   int32(row[3].(int64)), // google, you are
-			Maxlen: int32(row[4].(int64)), // disgusting
-		}))] = struct{}{}
+	run.Staging, err = staging.Close()
+	if err != nil {
+		return err
 	}
 
-	now := []time.Time{time.Now()}
-	var id int
-	var in []*storedROAWithTime
-	for _, i := range origIn.Roas {
-		id++
-		// shut up I know its not correct terminology
-		ipandmask := strings.Split(i.Prefix, "/")
-		// probably doesnt need error checking
-		mask, _ := strconv.Atoi(ipandmask[1])
-
-		/*in = append(in, storedROA{
-			Asn:       i.Asn,
-			Prefix:    ipandmask[0],
-			MaxLength: i.MaxLength,
-			Ta:        i.Ta,
-			Subnet:    mask,
-		})*/
-
-		in = append(in, &storedROAWithTime{i.Asn, ipandmask[0], i.MaxLength, i.Ta, mask, now})
-
-		//go log.Traceln(debug)
-		//debug++
+	run.checkTAs(ctx, taCounts)
 
+	generated, ok := doc.Metadata.generatedAt()
+	if ok {
+		run.Generated = bigquery.NullTimestamp{Timestamp: generated, Valid: true}
+	} else {
+		generated = run.Fetched
 	}
 
-	log.Traceln("making buf table")
-	// make buf table
-
-	err = client.Dataset("historical").Table("buf").Delete(ctx)
+	err = mergeStaged(ctx, "buf", run.Fetched, generated)
 	if err != nil {
-		log.Errorln("Error Deleting buf: ", err)
-		err = nil
+		return err
 	}
-	err = client.Dataset("historical").Table("buf").Create(ctx,
-		&bigquery.TableMetadata{Schema: schema})
+	err = mergeExtras(ctx, run, doc, run.Fetched, generated)
 	if err != nil {
-		ErrorHandler(w, r, 500, "error creating buf", err)
-		return
+		return err
 	}
-
-	tmpinserter := client.Dataset("historical").Table("buf").Inserter()
-
-	var divided [][]*storedROAWithTime
-	chunk := 950
-	for i := 0; i < len(in); i += chunk {
-		end := i + chunk
-		if end > len(in) {
-			end = len(in)
-		}
-		divided = append(divided, in[i:end])
-	}
-	for _, i := range divided {
-		if len(i) == 0 {
-			log.Errorln("Divided array had len 0")
-			break
-		}
-		err = tmpinserter.Put(ctx, i)
-		if err != nil {
-			ErrorHandler(w, r, 500, "error putting updates", err)
-			continue
+	run.Provenance = staging.provenance
+	if run.Provenance > 0 {
+		if err := mergeProvenance(ctx, "buf", run.Fetched); err != nil {
+			return err
 		}
 	}
 
-	// now make one plus one equal 2
-	// historical-roas.historical.roas_arr
-	query = client.Query(`MERGE historical.roas_arr arr
-	USING historical.buf b
-	ON 	b.Asn = arr.asn AND arr.maxlen = b.MaxLength
-	AND b.Prefix = arr.prefix AND arr.ta = b.Ta
-	AND b.Subnet = arr.mask
-	WHEN MATCHED THEN
- 		UPDATE SET inserttimes = ARRAY_CONCAT(b.times, arr.inserttimes)
-	WHEN NOT MATCHED BY TARGET THEN
-		INSERT (asn, maxlen, prefix, ta, mask, inserttimes) VALUES (b.Asn, b.MaxLength, b.Prefix, b.Ta, b.Subnet, b.times)`)
-	job, err = query.Run(ctx)
+	changes, err := findChanges(ctx, run)
 	if err != nil {
-		ErrorHandler(w, r, 500, "Error with query", err)
-	}
-	status, _ = job.Wait(ctx)
-	if err := status.Err(); err != nil {
-		ErrorHandler(w, r, 500, "Error with query", err)
-		return
+		log.Errorln("error finding changes: ", err)
 	}
+	go notifyWatchlists(context.Background(), run.ID, run.Fetched, changes)
 
-	_, err = job.Read(ctx)
+	run.Events, err = findEvents(ctx, run)
 	if err != nil {
-		ErrorHandler(w, r, 500, "Error with query", err)
-		return
+		// the update's in, it's only the warnings that are missing
+		log.Errorln("error looking for suspicious changes: ", err)
 	}
 
-	log.Debugln("done updating")
+	// the index may have been reloaded while this ran, and the new one might
+	// have this run already
+	idx := currentIndex()
+	if idx != nil && idx.lastRun().Before(run.Fetched) {
+		if err := idx.addRun(run.Fetched, keys); err != nil {
+			log.Errorln("error adding run to index: ", err)
+		}
+	}
+	mailDigests(ctx, idx)
 
+	log.Debugln("done updating")
+	return nil
 }
 
-func downloadRARC() (*inputROAArr, error) {
-	var form inputROAArr
-
+// downloadRARC streams the current ROAs into staging, recording the fetch
+// time, payload hash and ROA count on run and archiving the raw payload if
+// that's enabled.
+func downloadRARC(ctx context.Context, run *updateRun, staging *stagingWriter) (inputROAArr, error) {
 	resp, err := http.Get(roaURL)
 	if err != nil {
-		return &form, err
+		return inputROAArr{}, err
 	}
 	defer resp.Body.Close()
+	run.Fetched = time.Now()
 
-	buf := new(bytes.Buffer)
-	buf.ReadFrom(resp.Body)
-	jsonIn := buf.String()
+	// hash (and maybe archive) the payload as it goes past the decoder
+	hash := sha256.New()
+	body := io.TeeReader(resp.Body, hash)
 
-	err = json.Unmarshal([]byte(jsonIn), &form)
+	var spool *archiveSpool
+	if archive != nil {
+		spool, err = newArchiveSpool()
+		if err != nil {
+			return inputROAArr{}, err
+		}
+		defer spool.Close()
+		body = io.TeeReader(body, spool)
+	}
+
+	doc, n, err := decodeRARC(body, stagingChunk, staging.Write)
 	if err != nil {
-		return &form, err
+		return doc, err
+	}
+	run.ROAs = n
+	// the decoder stops at the end of the object, the hash needs the rest
+	if _, err := io.Copy(ioutil.Discard, body); err != nil {
+		return doc, err
+	}
+	run.PayloadHash = hex.EncodeToString(hash.Sum(nil))
+
+	if spool != nil {
+		run.ArchiveKey, err = spool.store(ctx, run.Fetched, run.PayloadHash)
+		if err != nil {
+			// not worth losing the update over
+			log.Errorln("error archiving payload: ", err)
+			run.ArchiveKey = ""
+		}
 	}
 
-	return &form, nil
+	return doc, nil
 }
 
 //ErrorHandler is a function to handle HTTP errors
@@ -498,16 +622,23 @@ func ErrorHandler(resp http.ResponseWriter, req *http.Request, status int, alert
 ; modified to save storage
 create table roas_arr (
 	asn text,
+	asnum bigint,
 	prefix text,
+	family int,
+	startaddr bytea,
+	endaddr bytea,
 	maxlen int,
 	ta text,
 	mask int,
-	inserttimes TIMESTAMP WITHOUT TIME ZONE[]
+	inserttimes TIMESTAMP WITHOUT TIME ZONE[],
+	generatedtimes TIMESTAMP WITHOUT TIME ZONE[]
 );
 create table last_modified (
 	time TIMESTAMP WITHOUT TIME ZONE
 );
 create index idx_as on roas_arr (asn);
+create index idx_asnum on roas_arr (asnum);
+create index idx_range on roas_arr (family, startaddr, endaddr);
 create index idx_prefix_mask on roas_arr (prefix, mask);
 create index idx_prefix_mask_asn on roas_arr (prefix, mask, asn);
 */
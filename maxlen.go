@@ -0,0 +1,263 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"net/netip"
+	"sort"
+	"strconv"
+	"time"
+
+	pb "github.com/gidoBOSSftw5731/Historical-ROA/proto"
+)
+
+// loose says whether e's maxLength is longer than its prefix, which RFC 9319
+// says not to do unless every more-specific it allows is really announced
+func loose(e *roaEntry) bool {
+	return e.MaxLen > e.Prefix.Bits()
+}
+
+// permitted is how many more-specifics of e the maxLength allows, as a float
+// since a loose v6 ROA can allow more than fit in an int64
+func permitted(e *roaEntry) float64 {
+	return math.Ldexp(1, e.MaxLen-e.Prefix.Bits()+1) - 2
+}
+
+// looseCovered is the distinct more-specifics inside e's maxLength that the
+// same ASN has a ROA for, least specific first. There's no routing data here,
+// so ROAs are the best evidence of what's really announced.
+func (idx *roaIndex) looseCovered(e *roaEntry, l lookup) []*roaEntry {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	seen := make(map[netip.Prefix]bool)
+	var out []*roaEntry
+	add := func(c *roaEntry) {
+		if c.ASN != e.ASN || c.Prefix.Bits() <= e.Prefix.Bits() || c.Prefix.Bits() > e.MaxLen ||
+			!e.Prefix.Contains(c.Prefix.Addr()) || seen[c.Prefix] {
+			return
+		}
+		seen[c.Prefix] = true
+		out = append(out, c)
+	}
+
+	idx.root(e.Prefix).covered(e.Prefix, func(n *trieNode) {
+		for _, c := range n.roas {
+			if l.has(c) {
+				add(c)
+			}
+		}
+	})
+	for _, c := range l.asserted() {
+		add(c)
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Prefix.Bits() < out[j].Prefix.Bits() })
+	return out
+}
+
+// looseROA reports on a loose ROA
+func (idx *roaIndex) looseROA(e *roaEntry, l lookup) *pb.LooseROA {
+	covered := idx.looseCovered(e, l)
+	out := &pb.LooseROA{
+		Roa:       entryResult(e),
+		Permitted: permitted(e),
+		Covered:   entryResults(covered),
+		Minimal:   []string{e.Prefix.String()},
+	}
+	for _, c := range covered {
+		out.Minimal = append(out.Minimal, c.Prefix.String())
+	}
+	switch {
+	case len(covered) == 0:
+		out.State = "unused"
+	case float64(len(covered)) < out.Permitted:
+		out.State = "partial"
+	default:
+		out.State = "complete"
+	}
+	return out
+}
+
+// looseSeries is how many ROAs something had in every run, and how many were
+// loose
+type looseSeries struct {
+	roas, loose []int64
+}
+
+// looseCounts is loose ROA counts in every run for each key, by difference
+// arrays over the spans like taCounts. key returns false to skip an entry.
+func (idx *roaIndex) looseCounts(key func(*roaEntry) (string, bool)) map[string]looseSeries {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	counts := make(map[string]looseSeries)
+	for _, e := range idx.byKey {
+		k, ok := key(e)
+		if !ok {
+			continue
+		}
+		c, ok := counts[k]
+		if !ok {
+			c = looseSeries{make([]int64, len(idx.runs)+1), make([]int64, len(idx.runs)+1)}
+			counts[k] = c
+		}
+		for _, s := range e.spans {
+			c.roas[s[0]]++
+			c.roas[s[1]+1]--
+			if loose(e) {
+				c.loose[s[0]]++
+				c.loose[s[1]+1]--
+			}
+		}
+	}
+
+	for k, c := range counts {
+		for i := 1; i < len(c.roas); i++ {
+			c.roas[i] += c.roas[i-1]
+			c.loose[i] += c.loose[i-1]
+		}
+		counts[k] = looseSeries{c.roas[:len(idx.runs)], c.loose[:len(idx.runs)]}
+	}
+	return counts
+}
+
+// looseCount tallies one more ROA for key
+func looseCount(m map[string]*pb.LooseCount, key string, e *roaEntry) {
+	c, ok := m[key]
+	if !ok {
+		c = &pb.LooseCount{Key: key}
+		m[key] = c
+	}
+	c.Roas++
+	if loose(e) {
+		c.Loose++
+	}
+}
+
+// maxLenAPI is the RFC 9319 maxLength report: how many ROAs have a maxLength
+// longer than their prefix, by TA and by ASN. For one ASN, or with
+// roas=true, it lists the loose ROAs with the more-specifics the same ASN
+// has ROAs for, and the minimal ROAs that would do instead. history=true
+// adds the counts in every update between from and to, by TA or for asn,
+// without bridging or SLURM. limit is how many ASNs to list, 100 by default.
+//
+//	/api/maxlen[?time=...][&asn=AS64496][&ta=...][&roas=true][&history=true][&from=...][&to=...][&limit=100][&bridge=true][&slurm=true]
+func maxLenAPI(w http.ResponseWriter, r *http.Request) {
+	idx := indexOr503(w, r)
+	if idx == nil {
+		return
+	}
+
+	var asn uint32
+	byASN := r.FormValue("asn") != ""
+	if byASN {
+		var err error
+		asn, err = parseASN(r.FormValue("asn"))
+		if err != nil {
+			ErrorHandler(w, r, 400, "Bad ASN", err)
+			return
+		}
+	}
+	limit := 100
+	if s := r.FormValue("limit"); s != "" {
+		var err error
+		limit, err = strconv.Atoi(s)
+		if err != nil || limit < 0 {
+			ErrorHandler(w, r, 400, "Bad limit", err)
+			return
+		}
+	}
+	from, to, ok := timeRange(w, r)
+	if !ok {
+		return
+	}
+	l, ok := indexLookup(w, r, idx)
+	if !ok {
+		return
+	}
+
+	at := idx.runTime(l.run)
+	out := &pb.MaxLenReport{Unixtime: at.Unix(), RFC3339Time: at.Format(time.RFC3339)}
+	tas := make(map[string]*pb.LooseCount)
+	asns := make(map[string]*pb.LooseCount)
+	var looseROAs []*roaEntry
+	for _, e := range idx.snapshot(l) {
+		if byASN && e.ASN != asn {
+			continue
+		}
+		out.Roas++
+		looseCount(tas, e.TA, e)
+		looseCount(asns, fmt.Sprintf("AS%d", e.ASN), e)
+		if loose(e) {
+			out.Loose++
+			looseROAs = append(looseROAs, e)
+		}
+	}
+
+	for _, c := range tas {
+		out.Tas = append(out.Tas, c)
+	}
+	sort.Slice(out.Tas, func(i, j int) bool { return out.Tas[i].Key < out.Tas[j].Key })
+	for _, c := range asns {
+		if c.Loose > 0 {
+			out.Asns = append(out.Asns, c)
+		}
+	}
+	sort.Slice(out.Asns, func(i, j int) bool {
+		if out.Asns[i].Loose != out.Asns[j].Loose {
+			return out.Asns[i].Loose > out.Asns[j].Loose
+		}
+		return out.Asns[i].Key < out.Asns[j].Key
+	})
+	if limit > 0 && len(out.Asns) > limit {
+		out.Asns = out.Asns[:limit]
+	}
+	for _, cs := range [][]*pb.LooseCount{out.Tas, out.Asns} {
+		for _, c := range cs {
+			c.Unixtime, c.RFC3339Time = out.Unixtime, out.RFC3339Time
+		}
+	}
+
+	if byASN || r.FormValue("roas") == "true" {
+		sortExport(looseROAs)
+		for _, e := range looseROAs {
+			out.Looseroas = append(out.Looseroas, idx.looseROA(e, l))
+		}
+	}
+
+	if r.FormValue("history") == "true" {
+		series := idx.looseCounts(func(e *roaEntry) (string, bool) {
+			if l.keep != nil && !l.keep(e) {
+				return "", false
+			}
+			if byASN {
+				return fmt.Sprintf("AS%d", e.ASN), e.ASN == asn
+			}
+			return e.TA, true
+		})
+		idx.mu.RLock()
+		runs := idx.runs
+		idx.mu.RUnlock()
+
+		for key, s := range series {
+			h := &pb.LooseHistory{Key: key}
+			for i := range s.roas {
+				if runs[i].Before(from) || runs[i].After(to) || s.roas[i] == 0 {
+					continue
+				}
+				h.Counts = append(h.Counts, &pb.LooseCount{
+					Key:         key,
+					Unixtime:    runs[i].Unix(),
+					RFC3339Time: runs[i].Format(time.RFC3339),
+					Roas:        s.roas[i],
+					Loose:       s.loose[i],
+				})
+			}
+			out.History = append(out.History, h)
+		}
+		sort.Slice(out.History, func(i, j int) bool { return out.History[i].Key < out.History[j].Key })
+	}
+
+	writeProto(w, out)
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+
+	"cloud.google.com/go/bigquery"
+	"github.com/gidoBOSSftw5731/log"
+)
+
+// asnum, family, startaddr and endaddr worked out from asn, prefix and mask,
+// for rows written before the columns existed. They're NULL for anything
+// that won't parse. See prefixRange, IPv4 is stored IPv4-mapped.
+const (
+	asnumSQL     = `SAFE_CAST(REGEXP_EXTRACT(asn, r'^(?i)AS(\d+)$') AS INT64)`
+	familySQL    = `IF(STRPOS(prefix, ':') > 0, 6, 4)`
+	startaddrSQL = `IF(STRPOS(prefix, ':') > 0,
+		SAFE.NET.IP_FROM_STRING(prefix) & SAFE.NET.IP_NET_MASK(16, mask),
+		CONCAT(FROM_HEX('00000000000000000000ffff'),
+			SAFE.NET.IP_FROM_STRING(prefix) & SAFE.NET.IP_NET_MASK(4, mask)))`
+	endaddrSQL = `IF(STRPOS(prefix, ':') > 0,
+		SAFE.NET.IP_FROM_STRING(prefix) | ~SAFE.NET.IP_NET_MASK(16, mask),
+		CONCAT(FROM_HEX('00000000000000000000ffff'),
+			SAFE.NET.IP_FROM_STRING(prefix) | ~SAFE.NET.IP_NET_MASK(4, mask)))`
+)
+
+// asnumColumn is asnum, falling back to asn on rows migrate hasn't got to
+const asnumColumn = `IFNULL(asnum, ` + asnumSQL + `)`
+
+// migrations fill in columns on rows written before the column existed.
+// Rows that are still being seen get filled in by the next update anyway,
+// these are for everything else. Lookups work them out on the fly until
+// then. They're all safe to run more than once. left counts the rows a
+// migration couldn't fill in.
+var migrations = []struct {
+	name string
+	sql  string
+	left string
+}{
+	{"asnum", `UPDATE historical.roas_arr SET asnum = ` + asnumSQL + `
+	WHERE asnum IS NULL`,
+		`SELECT COUNT(*) FROM historical.roas_arr WHERE asnum IS NULL`},
+	{"addrrange", `UPDATE historical.roas_arr SET
+		family = ` + familySQL + `,
+		startaddr = ` + startaddrSQL + `,
+		endaddr = ` + endaddrSQL + `
+	WHERE startaddr IS NULL`,
+		`SELECT COUNT(*) FROM historical.roas_arr WHERE startaddr IS NULL`},
+}
+
+// roasClustering keeps rows for nearby prefixes together, which is as close
+// as BigQuery gets to an index for the startaddr/endaddr range checks
+var roasClustering = []string{"family", "startaddr"}
+
+// migrateCmd brings roas_arr up to date with roaRow
+func migrateCmd(args []string) error {
+	fs := flag.NewFlagSet("migrate", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: Historical-ROA migrate")
+		fmt.Fprintln(fs.Output(), "adds any missing columns to roas_arr, fills them in for old rows and sets up clustering.")
+	}
+	fs.Parse(args)
+	if fs.NArg() != 0 {
+		fs.Usage()
+		return errors.New("migrate takes no arguments")
+	}
+
+	ctx := context.Background()
+
+	table, err := ensureTable(ctx, "roas_arr", roaRow{})
+	if err != nil {
+		return fmt.Errorf("error updating roas_arr: %v", err)
+	}
+	if err := ensureClustering(ctx, table, roasClustering); err != nil {
+		return fmt.Errorf("error clustering roas_arr: %v", err)
+	}
+
+	for _, m := range migrations {
+		log.Debugf("running migration %v", m.name)
+		if _, err := runQuery(ctx, client.Query(m.sql)); err != nil {
+			return fmt.Errorf("migration %v: %v", m.name, err)
+		}
+
+		it, err := runQuery(ctx, client.Query(m.left))
+		if err != nil {
+			return fmt.Errorf("migration %v: %v", m.name, err)
+		}
+		var row []bigquery.Value
+		if err := it.Next(&row); err != nil {
+			return fmt.Errorf("migration %v: %v", m.name, err)
+		}
+		if n := row[0].(int64); n > 0 {
+			// they can't be fixed up, but lookups won't find them either
+			log.Errorf("migration %v left %v rows that don't parse", m.name, n)
+		}
+	}
+	return nil
+}
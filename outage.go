@@ -0,0 +1,146 @@
+package main
+
+import (
+	"context"
+	"sort"
+
+	"github.com/gidoBOSSftw5731/log"
+	"google.golang.org/api/iterator"
+)
+
+// A TA is suspected to be down (its repository didn't publish, or the
+// validator couldn't fetch it) when it has less than outageFraction of the
+// ROAs it had in its last good update. Small TAs swing about too much to
+// tell, so they need at least outageMinROAs first.
+const (
+	outageFraction = 0.5
+	outageMinROAs  = 50
+	// bridgeMax is the most runs in a row an outage can last and still be
+	// bridged, anything longer is more likely real
+	bridgeMax = 6
+)
+
+// taRunCount is how many ROAs a TA had in a run
+type taRunCount struct {
+	TA   string
+	ROAs int
+}
+
+// taOutage is a suspected TA outage in a run, Baseline is the count from the
+// TA's last good run
+type taOutage struct {
+	TA       string
+	Baseline int
+	ROAs     int
+}
+
+func collapsed(baseline, n int64) bool {
+	return baseline >= outageMinROAs && float64(n) < float64(baseline)*outageFraction
+}
+
+// taBaselines is what each TA had in the last good update, which is the last
+// run that counted them unless it was already in an outage
+func taBaselines(ctx context.Context) (map[string]int, error) {
+	it, err := runQuery(ctx, client.Query(`SELECT TAs, Outages FROM historical.runs
+	WHERE Status = "ok" AND ARRAY_LENGTH(TAs) > 0
+	ORDER BY Fetched DESC LIMIT 1`))
+	if err != nil {
+		return nil, err
+	}
+
+	var row struct {
+		TAs     []taRunCount
+		Outages []taOutage
+	}
+	base := make(map[string]int)
+	err = it.Next(&row)
+	if err == iterator.Done {
+		return base, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for _, c := range row.TAs {
+		base[c.TA] = c.ROAs
+	}
+	for _, o := range row.Outages {
+		base[o.TA] = o.Baseline
+	}
+	return base, nil
+}
+
+// checkTAs records how many ROAs each TA had in the run, and notes any TA
+// that looks like it's having an outage. The ROAs are still merged as they
+// are, it's up to queries whether to bridge over it.
+func (u *updateRun) checkTAs(ctx context.Context, counts map[string]int) {
+	for ta, n := range counts {
+		u.TAs = append(u.TAs, taRunCount{ta, n})
+	}
+	sort.Slice(u.TAs, func(i, j int) bool { return u.TAs[i].TA < u.TAs[j].TA })
+
+	base, err := taBaselines(ctx)
+	if err != nil {
+		log.Errorln("can't check for TA outages: ", err)
+		return
+	}
+	for ta, b := range base {
+		if collapsed(int64(b), int64(counts[ta])) {
+			u.Outages = append(u.Outages, taOutage{ta, b, counts[ta]})
+			log.Errorf("suspected outage of TA %v: %v ROAs, down from %v", ta, counts[ta], b)
+		}
+	}
+	sort.Slice(u.Outages, func(i, j int) bool { return u.Outages[i].TA < u.Outages[j].TA })
+}
+
+// findOutages finds the runs where a TA with counts c was suspected down,
+// the same way checkTAs does, as [first, last] runs
+func findOutages(c []int64) [][2]int32 {
+	var out [][2]int32
+	var baseline int64
+	start := -1
+	for i, n := range c {
+		if collapsed(baseline, n) {
+			if start == -1 {
+				start = i
+			}
+			continue
+		}
+		if start != -1 {
+			out = append(out, [2]int32{int32(start), int32(i - 1)})
+			start = -1
+		}
+		baseline = n
+	}
+	if start != -1 {
+		out = append(out, [2]int32{int32(start), int32(len(c) - 1)})
+	}
+	return out
+}
+
+// bridgeable is the outages short enough to bridge by TA, and how many runs
+// the index had when they were found. They only change with a new run, so
+// they're cached until then.
+func (idx *roaIndex) bridgeable() (map[string][][2]int32, int) {
+	idx.outageMu.Lock()
+	defer idx.outageMu.Unlock()
+
+	idx.mu.RLock()
+	runs := len(idx.runs)
+	idx.mu.RUnlock()
+	if idx.outages != nil && idx.outageRuns == runs {
+		return idx.outages, idx.outageRuns
+	}
+
+	outages := make(map[string][][2]int32)
+	for ta, c := range idx.taCounts() {
+		runs = len(c)
+		for _, o := range findOutages(c) {
+			if o[1]-o[0] < bridgeMax {
+				outages[ta] = append(outages[ta], o)
+			}
+		}
+	}
+	idx.outages, idx.outageRuns = outages, runs
+	return outages, runs
+}
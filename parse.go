@@ -0,0 +1,242 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// inputMetadata is the metadata validators put alongside the ROAs.
+// Routinator has generated (unix) and generatedTime, rpki-client has
+// buildtime and, in newer versions, generated too.
+type inputMetadata struct {
+	// not a number in every version, so don't let it fail the whole parse
+	Generated     json.RawMessage `json:"generated"`
+	GeneratedTime string          `json:"generatedTime"`
+	BuildTime     string          `json:"buildtime"`
+}
+
+// generatedAt is when the validator made the set, if it told us
+func (m inputMetadata) generatedAt() (time.Time, bool) {
+	for _, s := range []string{m.GeneratedTime, m.BuildTime} {
+		if t, err := time.Parse(time.RFC3339, s); err == nil {
+			return t, true
+		}
+	}
+	if unix, err := strconv.ParseInt(strings.Trim(string(m.Generated), `"`), 10, 64); err == nil && unix > 0 {
+		return time.Unix(unix, 0), true
+	}
+	return time.Time{}, false
+}
+
+// UnmarshalJSON takes the asn as either a string like Routinator's "AS13335"
+// or a number like rpki-client's 13335, and stores it the Routinator way.
+func (i *inputROA) UnmarshalJSON(b []byte) error {
+	type plain inputROA
+	var v struct {
+		plain
+		Asn json.RawMessage `json:"asn"`
+	}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	*i = inputROA(v.plain)
+
+	// some validators put the source straight on the VRP
+	if len(i.Source) == 0 {
+		var src inputSource
+		if err := json.Unmarshal(b, &src); err == nil && !src.empty() {
+			i.Source = []inputSource{src}
+		}
+	}
+
+	var err error
+	i.Asn, err = jsonASN(v.Asn)
+	return err
+}
+
+// jsonASN reads an ASN that's either a string or a number, returning it the
+// Routinator way
+func jsonASN(b json.RawMessage) (string, error) {
+	if len(b) == 0 {
+		return "", nil
+	}
+	if b[0] == '"' {
+		var s string
+		err := json.Unmarshal(b, &s)
+		return s, err
+	}
+	var asn uint32
+	if err := json.Unmarshal(b, &asn); err != nil {
+		return "", fmt.Errorf("bad asn %s: %v", b, err)
+	}
+	return fmt.Sprintf("AS%d", asn), nil
+}
+
+// decodeRARC walks validator JSON from r, handing ROAs to fn in batches of
+// up to batch as it goes so the whole set is never in memory at once.
+// It returns everything else in the document (there aren't many ASPAs or
+// router keys) and how many ROAs there were.
+func decodeRARC(r io.Reader, batch int, fn func([]inputROA) error) (inputROAArr, int, error) {
+	var doc inputROAArr
+	var n int
+
+	dec := json.NewDecoder(r)
+	if err := expectDelim(dec, '{'); err != nil {
+		return doc, n, err
+	}
+
+	for dec.More() {
+		key, err := dec.Token()
+		if err != nil {
+			return doc, n, err
+		}
+
+		switch key {
+		case "metadata":
+			err = dec.Decode(&doc.Metadata)
+		case "aspas":
+			err = dec.Decode(&doc.ASPAs)
+		case "routerKeys", "bgpsec_keys":
+			// Routinator and rpki-client respectively
+			var keys []inputRouterKey
+			err = dec.Decode(&keys)
+			doc.RouterKeys = append(doc.RouterKeys, keys...)
+		case "roas":
+			n, err = decodeROAs(dec, batch, fn)
+		default:
+			// not ours, skip it
+			var skip json.RawMessage
+			err = dec.Decode(&skip)
+		}
+		if err != nil {
+			return doc, n, fmt.Errorf("decoding %v: %v", key, err)
+		}
+	}
+
+	return doc, n, expectDelim(dec, '}')
+}
+
+// decodeROAs reads the roas array one element at a time
+func decodeROAs(dec *json.Decoder, batch int, fn func([]inputROA) error) (int, error) {
+	if err := expectDelim(dec, '['); err != nil {
+		return 0, err
+	}
+
+	var n int
+	roas := make([]inputROA, 0, batch)
+	for dec.More() {
+		roas = append(roas, inputROA{})
+		if err := dec.Decode(&roas[len(roas)-1]); err != nil {
+			return n, err
+		}
+		n++
+
+		if len(roas) >= batch {
+			if err := fn(roas); err != nil {
+				return n, err
+			}
+			roas = roas[:0]
+		}
+	}
+	if len(roas) > 0 {
+		if err := fn(roas); err != nil {
+			return n, err
+		}
+	}
+
+	return n, expectDelim(dec, ']')
+}
+
+func expectDelim(dec *json.Decoder, want json.Delim) error {
+	t, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if d, ok := t.(json.Delim); !ok || d != want {
+		return fmt.Errorf("expected %v, got %v", want, t)
+	}
+	return nil
+}
+
+// decodeCSV reads validator CSV output, as written by Routinator and
+// rpki-client: a header line then ASN,IP Prefix,Max Length,Trust Anchor
+// columns in any order. Columns we don't know are ignored. Like decodeRARC
+// it hands the ROAs to fn in batches and returns how many there were.
+func decodeCSV(r io.Reader, batch int, fn func([]inputROA) error) (int, error) {
+	var n int
+
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+
+	header, err := cr.Read()
+	if err != nil {
+		return n, fmt.Errorf("reading CSV header: %v", err)
+	}
+	cols := map[string]int{"asn": -1, "ip prefix": -1, "max length": -1, "trust anchor": -1}
+	for n, h := range header {
+		if _, ok := cols[strings.ToLower(strings.TrimSpace(h))]; ok {
+			cols[strings.ToLower(strings.TrimSpace(h))] = n
+		}
+	}
+	for c, n := range cols {
+		// not all validators put the TA in their CSV
+		if n == -1 && c != "trust anchor" {
+			return 0, fmt.Errorf("CSV has no %q column", c)
+		}
+	}
+
+	roas := make([]inputROA, 0, batch)
+	for {
+		rec, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return n, err
+		}
+
+		field := func(c string) string {
+			if n := cols[c]; n >= 0 && n < len(rec) {
+				return strings.TrimSpace(rec[n])
+			}
+			return ""
+		}
+
+		maxLen, err := strconv.Atoi(field("max length"))
+		if err != nil {
+			return n, fmt.Errorf("bad max length in %v: %v", rec, err)
+		}
+		asn := field("asn")
+		if !strings.HasPrefix(strings.ToUpper(asn), "AS") {
+			asn = "AS" + asn
+		}
+
+		roas = append(roas, inputROA{
+			Asn:       asn,
+			Prefix:    field("ip prefix"),
+			MaxLength: maxLen,
+			Ta:        field("trust anchor"),
+		})
+		n++
+
+		if len(roas) >= batch {
+			if err := fn(roas); err != nil {
+				return n, err
+			}
+			roas = roas[:0]
+		}
+	}
+	if len(roas) > 0 {
+		if err := fn(roas); err != nil {
+			return n, err
+		}
+	}
+
+	return n, nil
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"fmt"
+	"net/netip"
+
+	"cloud.google.com/go/bigquery"
+)
+
+// prefixRange is the first and last address in p as 16 bytes, with IPv4 in
+// its IPv4-mapped form. Comparing these as bytes orders them like addresses,
+// so containment checks become range checks.
+func prefixRange(p netip.Prefix) ([]byte, []byte) {
+	p = p.Masked()
+	start := p.Addr().As16()
+	end := start
+
+	// IPv4 is in the last 4 bytes, so its host bits start 96 bits in
+	offset := 0
+	if p.Addr().Is4() {
+		offset = 96
+	}
+	for bit := offset + p.Bits(); bit < 128; bit++ {
+		end[bit/8] |= 0x80 >> (bit % 8)
+	}
+
+	return start[:], end[:]
+}
+
+// prefixFamily is 4 or 6
+func prefixFamily(p netip.Prefix) int {
+	if p.Addr().Is4() {
+		return 4
+	}
+	return 6
+}
+
+// prefixCondition is a WHERE clause on roas_arr for ROAs matching p, where
+// match is one of
+//
+//	exact        ROAs for p itself
+//	covering     ROAs for p or anything less specific that contains it
+//	covered      ROAs for p or anything more specific inside it
+//	overlapping  ROAs that share any addresses with p
+func prefixCondition(match string, p netip.Prefix) (string, []bigquery.QueryParameter, error) {
+	start, end := prefixRange(p)
+	params := []bigquery.QueryParameter{
+		{Name: "prefix", Value: p.Addr().String()},
+		{Name: "mask", Value: p.Bits()},
+		{Name: "family", Value: prefixFamily(p)},
+		{Name: "startaddr", Value: start},
+		{Name: "endaddr", Value: end},
+	}
+
+	var cond string
+	switch match {
+	case "", "exact":
+		return "prefix = @prefix AND mask = @mask", params, nil
+	case "covering":
+		cond = "%[1]v = @family AND %[2]v <= @startaddr AND %[3]v >= @endaddr"
+	case "covered":
+		cond = "%[1]v = @family AND %[2]v >= @startaddr AND %[3]v <= @endaddr"
+	case "overlapping":
+		cond = "%[1]v = @family AND %[2]v <= @endaddr AND %[3]v >= @startaddr"
+	default:
+		return "", nil, fmt.Errorf("unknown match %q", match)
+	}
+	// rows migrate hasn't got to have no range yet, it's worked out from
+	// prefix for them. The columns are checked on their own for the rest
+	// so the clustering still helps.
+	return fmt.Sprintf("((%v) OR (startaddr IS NULL AND %v))",
+		fmt.Sprintf(cond, "family", "startaddr", "endaddr"),
+		fmt.Sprintf(cond, familySQL, startaddrSQL, endaddrSQL)), params, nil
+}
@@ -2,7 +2,7 @@
 
 // Code generated by protoc-gen-go. DO NOT EDIT.
 // versions:
-// 	protoc-gen-go v1.25.0-devel
+// 	protoc-gen-go v1.27.1
 // 	protoc        v3.12.4
 // source: rarc.proto
 
@@ -36,6 +36,17 @@ type ResultsFromDB struct {
 	Fullprefixrange string   `protobuf:"bytes,8,opt,name=fullprefixrange,proto3" json:"fullprefixrange,omitempty"`
 	Unixtimearr     []int64  `protobuf:"varint,9,rep,packed,name=unixtimearr,proto3" json:"unixtimearr,omitempty"`
 	RFC3339Timearr  []string `protobuf:"bytes,10,rep,name=RFC3339timearr,proto3" json:"RFC3339timearr,omitempty"`
+	// when the validator generated each of the sets in unixtimearr, this is
+	// the fetch time for anything older than the validator metadata
+	Generatedunixtimearr    []int64  `protobuf:"varint,11,rep,packed,name=generatedunixtimearr,proto3" json:"generatedunixtimearr,omitempty"`
+	RFC3339Generatedtimearr []string `protobuf:"bytes,12,rep,name=RFC3339generatedtimearr,proto3" json:"RFC3339generatedtimearr,omitempty"`
+	// ASN as a number, ASN is the display form ("AS54054")
+	Asnum uint32 `protobuf:"varint,13,opt,name=asnum,proto3" json:"asnum,omitempty"`
+	// where the ROA came from, if the validator said, newest first
+	Provenance []*Provenance `protobuf:"bytes,14,rep,name=provenance,proto3" json:"provenance,omitempty"`
+	// when the ROA was around, from unixtimearr. Missing unless the index is
+	// loaded, so always with INDEX=false.
+	Lifespan *Lifespan `protobuf:"bytes,15,opt,name=lifespan,proto3" json:"lifespan,omitempty"`
 }
 
 func (x *ResultsFromDB) Reset() {
@@ -133,6 +144,146 @@ func (x *ResultsFromDB) GetRFC3339Timearr() []string {
 	return nil
 }
 
+func (x *ResultsFromDB) GetGeneratedunixtimearr() []int64 {
+	if x != nil {
+		return x.Generatedunixtimearr
+	}
+	return nil
+}
+
+func (x *ResultsFromDB) GetRFC3339Generatedtimearr() []string {
+	if x != nil {
+		return x.RFC3339Generatedtimearr
+	}
+	return nil
+}
+
+func (x *ResultsFromDB) GetAsnum() uint32 {
+	if x != nil {
+		return x.Asnum
+	}
+	return 0
+}
+
+func (x *ResultsFromDB) GetProvenance() []*Provenance {
+	if x != nil {
+		return x.Provenance
+	}
+	return nil
+}
+
+func (x *ResultsFromDB) GetLifespan() *Lifespan {
+	if x != nil {
+		return x.Lifespan
+	}
+	return nil
+}
+
+// Provenance is one source of a VRP: the .roa object, the EE certificate
+// that signed it and its validity, and every update it was seen from there.
+type Provenance struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Uri              string   `protobuf:"bytes,1,opt,name=uri,proto3" json:"uri,omitempty"`
+	SKI              string   `protobuf:"bytes,2,opt,name=SKI,proto3" json:"SKI,omitempty"`
+	Notbefore        int64    `protobuf:"varint,3,opt,name=notbefore,proto3" json:"notbefore,omitempty"`
+	RFC3339Notbefore string   `protobuf:"bytes,4,opt,name=RFC3339notbefore,proto3" json:"RFC3339notbefore,omitempty"`
+	Notafter         int64    `protobuf:"varint,5,opt,name=notafter,proto3" json:"notafter,omitempty"`
+	RFC3339Notafter  string   `protobuf:"bytes,6,opt,name=RFC3339notafter,proto3" json:"RFC3339notafter,omitempty"`
+	Unixtimearr      []int64  `protobuf:"varint,7,rep,packed,name=unixtimearr,proto3" json:"unixtimearr,omitempty"`
+	RFC3339Timearr   []string `protobuf:"bytes,8,rep,name=RFC3339timearr,proto3" json:"RFC3339timearr,omitempty"`
+}
+
+func (x *Provenance) Reset() {
+	*x = Provenance{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rarc_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Provenance) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Provenance) ProtoMessage() {}
+
+func (x *Provenance) ProtoReflect() protoreflect.Message {
+	mi := &file_rarc_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Provenance.ProtoReflect.Descriptor instead.
+func (*Provenance) Descriptor() ([]byte, []int) {
+	return file_rarc_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Provenance) GetUri() string {
+	if x != nil {
+		return x.Uri
+	}
+	return ""
+}
+
+func (x *Provenance) GetSKI() string {
+	if x != nil {
+		return x.SKI
+	}
+	return ""
+}
+
+func (x *Provenance) GetNotbefore() int64 {
+	if x != nil {
+		return x.Notbefore
+	}
+	return 0
+}
+
+func (x *Provenance) GetRFC3339Notbefore() string {
+	if x != nil {
+		return x.RFC3339Notbefore
+	}
+	return ""
+}
+
+func (x *Provenance) GetNotafter() int64 {
+	if x != nil {
+		return x.Notafter
+	}
+	return 0
+}
+
+func (x *Provenance) GetRFC3339Notafter() string {
+	if x != nil {
+		return x.RFC3339Notafter
+	}
+	return ""
+}
+
+func (x *Provenance) GetUnixtimearr() []int64 {
+	if x != nil {
+		return x.Unixtimearr
+	}
+	return nil
+}
+
+func (x *Provenance) GetRFC3339Timearr() []string {
+	if x != nil {
+		return x.RFC3339Timearr
+	}
+	return nil
+}
+
 // ResultsFromDBRFC3339 was used before ResultsFromDB had human readable time
 // included by default. This is therefore DEPRECATED and should NOT be used.
 type ResultsFromDBRFC3339 struct {
@@ -153,7 +304,7 @@ type ResultsFromDBRFC3339 struct {
 func (x *ResultsFromDBRFC3339) Reset() {
 	*x = ResultsFromDBRFC3339{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_rarc_proto_msgTypes[1]
+		mi := &file_rarc_proto_msgTypes[2]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -166,7 +317,7 @@ func (x *ResultsFromDBRFC3339) String() string {
 func (*ResultsFromDBRFC3339) ProtoMessage() {}
 
 func (x *ResultsFromDBRFC3339) ProtoReflect() protoreflect.Message {
-	mi := &file_rarc_proto_msgTypes[1]
+	mi := &file_rarc_proto_msgTypes[2]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -179,7 +330,7 @@ func (x *ResultsFromDBRFC3339) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ResultsFromDBRFC3339.ProtoReflect.Descriptor instead.
 func (*ResultsFromDBRFC3339) Descriptor() ([]byte, []int) {
-	return file_rarc_proto_rawDescGZIP(), []int{1}
+	return file_rarc_proto_rawDescGZIP(), []int{2}
 }
 
 func (x *ResultsFromDBRFC3339) GetASN() string {
@@ -249,7 +400,7 @@ type ResultArr struct {
 func (x *ResultArr) Reset() {
 	*x = ResultArr{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_rarc_proto_msgTypes[2]
+		mi := &file_rarc_proto_msgTypes[3]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -262,7 +413,7 @@ func (x *ResultArr) String() string {
 func (*ResultArr) ProtoMessage() {}
 
 func (x *ResultArr) ProtoReflect() protoreflect.Message {
-	mi := &file_rarc_proto_msgTypes[2]
+	mi := &file_rarc_proto_msgTypes[3]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -275,7 +426,7 @@ func (x *ResultArr) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ResultArr.ProtoReflect.Descriptor instead.
 func (*ResultArr) Descriptor() ([]byte, []int) {
-	return file_rarc_proto_rawDescGZIP(), []int{2}
+	return file_rarc_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *ResultArr) GetResults() []*ResultsFromDB {
@@ -285,108 +436,3220 @@ func (x *ResultArr) GetResults() []*ResultsFromDB {
 	return nil
 }
 
-var File_rarc_proto protoreflect.FileDescriptor
+// Validity is the RFC 6811 origin validation state of a route at a time.
+type Validity struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
 
-var file_rarc_proto_rawDesc = []byte{
-	0x0a, 0x0a, 0x72, 0x61, 0x72, 0x63, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x09, 0x72, 0x61,
-	0x72, 0x63, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x89, 0x02, 0x0a, 0x0d, 0x52, 0x65, 0x73, 0x75,
-	0x6c, 0x74, 0x73, 0x46, 0x72, 0x6f, 0x6d, 0x44, 0x42, 0x12, 0x10, 0x0a, 0x03, 0x41, 0x53, 0x4e,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x41, 0x53, 0x4e, 0x12, 0x16, 0x0a, 0x06, 0x70,
-	0x72, 0x65, 0x66, 0x69, 0x78, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x70, 0x72, 0x65,
-	0x66, 0x69, 0x78, 0x12, 0x16, 0x0a, 0x06, 0x6d, 0x61, 0x78, 0x6c, 0x65, 0x6e, 0x18, 0x03, 0x20,
-	0x01, 0x28, 0x05, 0x52, 0x06, 0x6d, 0x61, 0x78, 0x6c, 0x65, 0x6e, 0x12, 0x0e, 0x0a, 0x02, 0x74,
-	0x61, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x74, 0x61, 0x12, 0x12, 0x0a, 0x04, 0x6d,
-	0x61, 0x73, 0x6b, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x6d, 0x61, 0x73, 0x6b, 0x12,
-	0x1e, 0x0a, 0x0a, 0x66, 0x75, 0x6c, 0x6c, 0x70, 0x72, 0x65, 0x66, 0x69, 0x78, 0x18, 0x07, 0x20,
-	0x01, 0x28, 0x09, 0x52, 0x0a, 0x66, 0x75, 0x6c, 0x6c, 0x70, 0x72, 0x65, 0x66, 0x69, 0x78, 0x12,
-	0x28, 0x0a, 0x0f, 0x66, 0x75, 0x6c, 0x6c, 0x70, 0x72, 0x65, 0x66, 0x69, 0x78, 0x72, 0x61, 0x6e,
-	0x67, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x66, 0x75, 0x6c, 0x6c, 0x70, 0x72,
-	0x65, 0x66, 0x69, 0x78, 0x72, 0x61, 0x6e, 0x67, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x75, 0x6e, 0x69,
-	0x78, 0x74, 0x69, 0x6d, 0x65, 0x61, 0x72, 0x72, 0x18, 0x09, 0x20, 0x03, 0x28, 0x03, 0x52, 0x0b,
-	0x75, 0x6e, 0x69, 0x78, 0x74, 0x69, 0x6d, 0x65, 0x61, 0x72, 0x72, 0x12, 0x26, 0x0a, 0x0e, 0x52,
-	0x46, 0x43, 0x33, 0x33, 0x33, 0x39, 0x74, 0x69, 0x6d, 0x65, 0x61, 0x72, 0x72, 0x18, 0x0a, 0x20,
-	0x03, 0x28, 0x09, 0x52, 0x0e, 0x52, 0x46, 0x43, 0x33, 0x33, 0x33, 0x39, 0x74, 0x69, 0x6d, 0x65,
-	0x61, 0x72, 0x72, 0x22, 0xda, 0x01, 0x0a, 0x14, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x46,
-	0x72, 0x6f, 0x6d, 0x44, 0x42, 0x52, 0x46, 0x43, 0x33, 0x33, 0x33, 0x39, 0x12, 0x10, 0x0a, 0x03,
-	0x41, 0x53, 0x4e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x41, 0x53, 0x4e, 0x12, 0x16,
-	0x0a, 0x06, 0x70, 0x72, 0x65, 0x66, 0x69, 0x78, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06,
-	0x70, 0x72, 0x65, 0x66, 0x69, 0x78, 0x12, 0x16, 0x0a, 0x06, 0x6d, 0x61, 0x78, 0x6c, 0x65, 0x6e,
-	0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x6d, 0x61, 0x78, 0x6c, 0x65, 0x6e, 0x12, 0x0e,
-	0x0a, 0x02, 0x74, 0x61, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x74, 0x61, 0x12, 0x12,
-	0x0a, 0x04, 0x6d, 0x61, 0x73, 0x6b, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x6d, 0x61,
-	0x73, 0x6b, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x06, 0x20, 0x03, 0x28, 0x09,
-	0x52, 0x04, 0x74, 0x69, 0x6d, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x66, 0x75, 0x6c, 0x6c, 0x70, 0x72,
-	0x65, 0x66, 0x69, 0x78, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x66, 0x75, 0x6c, 0x6c,
-	0x70, 0x72, 0x65, 0x66, 0x69, 0x78, 0x12, 0x28, 0x0a, 0x0f, 0x66, 0x75, 0x6c, 0x6c, 0x70, 0x72,
-	0x65, 0x66, 0x69, 0x78, 0x72, 0x61, 0x6e, 0x67, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x0f, 0x66, 0x75, 0x6c, 0x6c, 0x70, 0x72, 0x65, 0x66, 0x69, 0x78, 0x72, 0x61, 0x6e, 0x67, 0x65,
-	0x22, 0x3f, 0x0a, 0x09, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x41, 0x72, 0x72, 0x12, 0x32, 0x0a,
-	0x07, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x18,
-	0x2e, 0x72, 0x61, 0x72, 0x63, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x52, 0x65, 0x73, 0x75, 0x6c,
-	0x74, 0x73, 0x46, 0x72, 0x6f, 0x6d, 0x44, 0x42, 0x52, 0x07, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74,
-	0x73, 0x42, 0x09, 0x5a, 0x07, 0x2e, 0x3b, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x06, 0x70, 0x72,
-	0x6f, 0x74, 0x6f, 0x33,
+	// "valid", "invalid" or "notfound"
+	State  string `protobuf:"bytes,1,opt,name=state,proto3" json:"state,omitempty"`
+	Prefix string `protobuf:"bytes,2,opt,name=prefix,proto3" json:"prefix,omitempty"`
+	ASN    string `protobuf:"bytes,3,opt,name=ASN,proto3" json:"ASN,omitempty"`
+	// the set the route was checked against
+	Unixtime    int64  `protobuf:"varint,4,opt,name=unixtime,proto3" json:"unixtime,omitempty"`
+	RFC3339Time string `protobuf:"bytes,5,opt,name=RFC3339time,proto3" json:"RFC3339time,omitempty"`
+	// covering ROAs that allow the route
+	Matched []*ResultsFromDB `protobuf:"bytes,6,rep,name=matched,proto3" json:"matched,omitempty"`
+	// covering ROAs that don't
+	Unmatched []*ResultsFromDB `protobuf:"bytes,7,rep,name=unmatched,proto3" json:"unmatched,omitempty"`
 }
 
-var (
-	file_rarc_proto_rawDescOnce sync.Once
-	file_rarc_proto_rawDescData = file_rarc_proto_rawDesc
-)
+func (x *Validity) Reset() {
+	*x = Validity{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rarc_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
 
-func file_rarc_proto_rawDescGZIP() []byte {
-	file_rarc_proto_rawDescOnce.Do(func() {
-		file_rarc_proto_rawDescData = protoimpl.X.CompressGZIP(file_rarc_proto_rawDescData)
-	})
-	return file_rarc_proto_rawDescData
+func (x *Validity) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-var file_rarc_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
-var file_rarc_proto_goTypes = []interface{}{
-	(*ResultsFromDB)(nil),        // 0: rarcproto.ResultsFromDB
-	(*ResultsFromDBRFC3339)(nil), // 1: rarcproto.ResultsFromDBRFC3339
-	(*ResultArr)(nil),            // 2: rarcproto.ResultArr
+func (*Validity) ProtoMessage() {}
+
+func (x *Validity) ProtoReflect() protoreflect.Message {
+	mi := &file_rarc_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
 }
-var file_rarc_proto_depIdxs = []int32{
-	0, // 0: rarcproto.ResultArr.results:type_name -> rarcproto.ResultsFromDB
-	1, // [1:1] is the sub-list for method output_type
-	1, // [1:1] is the sub-list for method input_type
-	1, // [1:1] is the sub-list for extension type_name
-	1, // [1:1] is the sub-list for extension extendee
-	0, // [0:1] is the sub-list for field type_name
+
+// Deprecated: Use Validity.ProtoReflect.Descriptor instead.
+func (*Validity) Descriptor() ([]byte, []int) {
+	return file_rarc_proto_rawDescGZIP(), []int{4}
 }
 
-func init() { file_rarc_proto_init() }
-func file_rarc_proto_init() {
-	if File_rarc_proto != nil {
-		return
+func (x *Validity) GetState() string {
+	if x != nil {
+		return x.State
 	}
-	if !protoimpl.UnsafeEnabled {
-		file_rarc_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ResultsFromDB); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-		file_rarc_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ResultsFromDBRFC3339); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
+	return ""
+}
+
+func (x *Validity) GetPrefix() string {
+	if x != nil {
+		return x.Prefix
+	}
+	return ""
+}
+
+func (x *Validity) GetASN() string {
+	if x != nil {
+		return x.ASN
+	}
+	return ""
+}
+
+func (x *Validity) GetUnixtime() int64 {
+	if x != nil {
+		return x.Unixtime
+	}
+	return 0
+}
+
+func (x *Validity) GetRFC3339Time() string {
+	if x != nil {
+		return x.RFC3339Time
+	}
+	return ""
+}
+
+func (x *Validity) GetMatched() []*ResultsFromDB {
+	if x != nil {
+		return x.Matched
+	}
+	return nil
+}
+
+func (x *Validity) GetUnmatched() []*ResultsFromDB {
+	if x != nil {
+		return x.Unmatched
+	}
+	return nil
+}
+
+// IndexStats describes the in memory ROA index.
+type IndexStats struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Loaded bool  `protobuf:"varint,1,opt,name=loaded,proto3" json:"loaded,omitempty"`
+	Roas   int64 `protobuf:"varint,2,opt,name=roas,proto3" json:"roas,omitempty"`
+	Nodes  int64 `protobuf:"varint,3,opt,name=nodes,proto3" json:"nodes,omitempty"`
+	Spans  int64 `protobuf:"varint,4,opt,name=spans,proto3" json:"spans,omitempty"`
+	Runs   int64 `protobuf:"varint,5,opt,name=runs,proto3" json:"runs,omitempty"`
+	// estimated size of the index itself
+	Bytes int64 `protobuf:"varint,6,opt,name=bytes,proto3" json:"bytes,omitempty"`
+	// from the Go runtime, for comparison
+	Heapbytes      int64  `protobuf:"varint,7,opt,name=heapbytes,proto3" json:"heapbytes,omitempty"`
+	RFC3339Loaded  string `protobuf:"bytes,8,opt,name=RFC3339loaded,proto3" json:"RFC3339loaded,omitempty"`
+	RFC3339Lastrun string `protobuf:"bytes,9,opt,name=RFC3339lastrun,proto3" json:"RFC3339lastrun,omitempty"`
+}
+
+func (x *IndexStats) Reset() {
+	*x = IndexStats{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rarc_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *IndexStats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IndexStats) ProtoMessage() {}
+
+func (x *IndexStats) ProtoReflect() protoreflect.Message {
+	mi := &file_rarc_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
 		}
-		file_rarc_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ResultArr); i {
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IndexStats.ProtoReflect.Descriptor instead.
+func (*IndexStats) Descriptor() ([]byte, []int) {
+	return file_rarc_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *IndexStats) GetLoaded() bool {
+	if x != nil {
+		return x.Loaded
+	}
+	return false
+}
+
+func (x *IndexStats) GetRoas() int64 {
+	if x != nil {
+		return x.Roas
+	}
+	return 0
+}
+
+func (x *IndexStats) GetNodes() int64 {
+	if x != nil {
+		return x.Nodes
+	}
+	return 0
+}
+
+func (x *IndexStats) GetSpans() int64 {
+	if x != nil {
+		return x.Spans
+	}
+	return 0
+}
+
+func (x *IndexStats) GetRuns() int64 {
+	if x != nil {
+		return x.Runs
+	}
+	return 0
+}
+
+func (x *IndexStats) GetBytes() int64 {
+	if x != nil {
+		return x.Bytes
+	}
+	return 0
+}
+
+func (x *IndexStats) GetHeapbytes() int64 {
+	if x != nil {
+		return x.Heapbytes
+	}
+	return 0
+}
+
+func (x *IndexStats) GetRFC3339Loaded() string {
+	if x != nil {
+		return x.RFC3339Loaded
+	}
+	return ""
+}
+
+func (x *IndexStats) GetRFC3339Lastrun() string {
+	if x != nil {
+		return x.RFC3339Lastrun
+	}
+	return ""
+}
+
+// TACount is how many ROAs a trust anchor had in one update.
+type TACount struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Unixtime    int64  `protobuf:"varint,1,opt,name=unixtime,proto3" json:"unixtime,omitempty"`
+	RFC3339Time string `protobuf:"bytes,2,opt,name=RFC3339time,proto3" json:"RFC3339time,omitempty"`
+	Roas        int64  `protobuf:"varint,3,opt,name=roas,proto3" json:"roas,omitempty"`
+}
+
+func (x *TACount) Reset() {
+	*x = TACount{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rarc_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TACount) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TACount) ProtoMessage() {}
+
+func (x *TACount) ProtoReflect() protoreflect.Message {
+	mi := &file_rarc_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TACount.ProtoReflect.Descriptor instead.
+func (*TACount) Descriptor() ([]byte, []int) {
+	return file_rarc_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *TACount) GetUnixtime() int64 {
+	if x != nil {
+		return x.Unixtime
+	}
+	return 0
+}
+
+func (x *TACount) GetRFC3339Time() string {
+	if x != nil {
+		return x.RFC3339Time
+	}
+	return ""
+}
+
+func (x *TACount) GetRoas() int64 {
+	if x != nil {
+		return x.Roas
+	}
+	return 0
+}
+
+// TrustAnchor is a trust anchor we've seen ROAs from.
+type TrustAnchor struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Ta               string `protobuf:"bytes,1,opt,name=ta,proto3" json:"ta,omitempty"`
+	Firstseen        int64  `protobuf:"varint,2,opt,name=firstseen,proto3" json:"firstseen,omitempty"`
+	RFC3339Firstseen string `protobuf:"bytes,3,opt,name=RFC3339firstseen,proto3" json:"RFC3339firstseen,omitempty"`
+	Lastseen         int64  `protobuf:"varint,4,opt,name=lastseen,proto3" json:"lastseen,omitempty"`
+	RFC3339Lastseen  string `protobuf:"bytes,5,opt,name=RFC3339lastseen,proto3" json:"RFC3339lastseen,omitempty"`
+	// ROAs in the latest update
+	Roas int64 `protobuf:"varint,6,opt,name=roas,proto3" json:"roas,omitempty"`
+	// ROAs in every update, only filled in when asked for
+	Counts []*TACount `protobuf:"bytes,7,rep,name=counts,proto3" json:"counts,omitempty"`
+	// updates where the TA looked like it was down
+	Outages []*TAOutage `protobuf:"bytes,8,rep,name=outages,proto3" json:"outages,omitempty"`
+}
+
+func (x *TrustAnchor) Reset() {
+	*x = TrustAnchor{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rarc_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TrustAnchor) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TrustAnchor) ProtoMessage() {}
+
+func (x *TrustAnchor) ProtoReflect() protoreflect.Message {
+	mi := &file_rarc_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TrustAnchor.ProtoReflect.Descriptor instead.
+func (*TrustAnchor) Descriptor() ([]byte, []int) {
+	return file_rarc_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *TrustAnchor) GetTa() string {
+	if x != nil {
+		return x.Ta
+	}
+	return ""
+}
+
+func (x *TrustAnchor) GetFirstseen() int64 {
+	if x != nil {
+		return x.Firstseen
+	}
+	return 0
+}
+
+func (x *TrustAnchor) GetRFC3339Firstseen() string {
+	if x != nil {
+		return x.RFC3339Firstseen
+	}
+	return ""
+}
+
+func (x *TrustAnchor) GetLastseen() int64 {
+	if x != nil {
+		return x.Lastseen
+	}
+	return 0
+}
+
+func (x *TrustAnchor) GetRFC3339Lastseen() string {
+	if x != nil {
+		return x.RFC3339Lastseen
+	}
+	return ""
+}
+
+func (x *TrustAnchor) GetRoas() int64 {
+	if x != nil {
+		return x.Roas
+	}
+	return 0
+}
+
+func (x *TrustAnchor) GetCounts() []*TACount {
+	if x != nil {
+		return x.Counts
+	}
+	return nil
+}
+
+func (x *TrustAnchor) GetOutages() []*TAOutage {
+	if x != nil {
+		return x.Outages
+	}
+	return nil
+}
+
+// TAOutage is a run of updates where a trust anchor had far fewer ROAs than
+// before, which is usually its repository or the validator failing rather
+// than the ROAs really going.
+type TAOutage struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Start        int64  `protobuf:"varint,1,opt,name=start,proto3" json:"start,omitempty"`
+	RFC3339Start string `protobuf:"bytes,2,opt,name=RFC3339start,proto3" json:"RFC3339start,omitempty"`
+	End          int64  `protobuf:"varint,3,opt,name=end,proto3" json:"end,omitempty"`
+	RFC3339End   string `protobuf:"bytes,4,opt,name=RFC3339end,proto3" json:"RFC3339end,omitempty"`
+	// ROAs before it started
+	Baseline int64 `protobuf:"varint,5,opt,name=baseline,proto3" json:"baseline,omitempty"`
+	// over, and short enough that bridge=true fills it in
+	Bridged bool `protobuf:"varint,6,opt,name=bridged,proto3" json:"bridged,omitempty"`
+}
+
+func (x *TAOutage) Reset() {
+	*x = TAOutage{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rarc_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TAOutage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TAOutage) ProtoMessage() {}
+
+func (x *TAOutage) ProtoReflect() protoreflect.Message {
+	mi := &file_rarc_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TAOutage.ProtoReflect.Descriptor instead.
+func (*TAOutage) Descriptor() ([]byte, []int) {
+	return file_rarc_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *TAOutage) GetStart() int64 {
+	if x != nil {
+		return x.Start
+	}
+	return 0
+}
+
+func (x *TAOutage) GetRFC3339Start() string {
+	if x != nil {
+		return x.RFC3339Start
+	}
+	return ""
+}
+
+func (x *TAOutage) GetEnd() int64 {
+	if x != nil {
+		return x.End
+	}
+	return 0
+}
+
+func (x *TAOutage) GetRFC3339End() string {
+	if x != nil {
+		return x.RFC3339End
+	}
+	return ""
+}
+
+func (x *TAOutage) GetBaseline() int64 {
+	if x != nil {
+		return x.Baseline
+	}
+	return 0
+}
+
+func (x *TAOutage) GetBridged() bool {
+	if x != nil {
+		return x.Bridged
+	}
+	return false
+}
+
+type TrustAnchors struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Tas []*TrustAnchor `protobuf:"bytes,1,rep,name=tas,proto3" json:"tas,omitempty"`
+}
+
+func (x *TrustAnchors) Reset() {
+	*x = TrustAnchors{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rarc_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TrustAnchors) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TrustAnchors) ProtoMessage() {}
+
+func (x *TrustAnchors) ProtoReflect() protoreflect.Message {
+	mi := &file_rarc_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TrustAnchors.ProtoReflect.Descriptor instead.
+func (*TrustAnchors) Descriptor() ([]byte, []int) {
+	return file_rarc_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *TrustAnchors) GetTas() []*TrustAnchor {
+	if x != nil {
+		return x.Tas
+	}
+	return nil
+}
+
+// ASPA is an ASPA object's customer and provider set, and every update it
+// was in.
+type ASPA struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Customer       string   `protobuf:"bytes,1,opt,name=customer,proto3" json:"customer,omitempty"`
+	Customerasnum  uint32   `protobuf:"varint,2,opt,name=customerasnum,proto3" json:"customerasnum,omitempty"`
+	Providers      []string `protobuf:"bytes,3,rep,name=providers,proto3" json:"providers,omitempty"`
+	Ta             string   `protobuf:"bytes,4,opt,name=ta,proto3" json:"ta,omitempty"`
+	Unixtimearr    []int64  `protobuf:"varint,5,rep,packed,name=unixtimearr,proto3" json:"unixtimearr,omitempty"`
+	RFC3339Timearr []string `protobuf:"bytes,6,rep,name=RFC3339timearr,proto3" json:"RFC3339timearr,omitempty"`
+}
+
+func (x *ASPA) Reset() {
+	*x = ASPA{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rarc_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ASPA) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ASPA) ProtoMessage() {}
+
+func (x *ASPA) ProtoReflect() protoreflect.Message {
+	mi := &file_rarc_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ASPA.ProtoReflect.Descriptor instead.
+func (*ASPA) Descriptor() ([]byte, []int) {
+	return file_rarc_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ASPA) GetCustomer() string {
+	if x != nil {
+		return x.Customer
+	}
+	return ""
+}
+
+func (x *ASPA) GetCustomerasnum() uint32 {
+	if x != nil {
+		return x.Customerasnum
+	}
+	return 0
+}
+
+func (x *ASPA) GetProviders() []string {
+	if x != nil {
+		return x.Providers
+	}
+	return nil
+}
+
+func (x *ASPA) GetTa() string {
+	if x != nil {
+		return x.Ta
+	}
+	return ""
+}
+
+func (x *ASPA) GetUnixtimearr() []int64 {
+	if x != nil {
+		return x.Unixtimearr
+	}
+	return nil
+}
+
+func (x *ASPA) GetRFC3339Timearr() []string {
+	if x != nil {
+		return x.RFC3339Timearr
+	}
+	return nil
+}
+
+type ASPAArr struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Aspas []*ASPA `protobuf:"bytes,1,rep,name=aspas,proto3" json:"aspas,omitempty"`
+}
+
+func (x *ASPAArr) Reset() {
+	*x = ASPAArr{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rarc_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ASPAArr) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ASPAArr) ProtoMessage() {}
+
+func (x *ASPAArr) ProtoReflect() protoreflect.Message {
+	mi := &file_rarc_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ASPAArr.ProtoReflect.Descriptor instead.
+func (*ASPAArr) Descriptor() ([]byte, []int) {
+	return file_rarc_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ASPAArr) GetAspas() []*ASPA {
+	if x != nil {
+		return x.Aspas
+	}
+	return nil
+}
+
+// RouterKey is a BGPsec router key, and every update it was in.
+type RouterKey struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ASN   string `protobuf:"bytes,1,opt,name=ASN,proto3" json:"ASN,omitempty"`
+	Asnum uint32 `protobuf:"varint,2,opt,name=asnum,proto3" json:"asnum,omitempty"`
+	// hex subject key identifier
+	SKI string `protobuf:"bytes,3,opt,name=SKI,proto3" json:"SKI,omitempty"`
+	// base64 DER SubjectPublicKeyInfo
+	Pubkey         string   `protobuf:"bytes,4,opt,name=pubkey,proto3" json:"pubkey,omitempty"`
+	Ta             string   `protobuf:"bytes,5,opt,name=ta,proto3" json:"ta,omitempty"`
+	Unixtimearr    []int64  `protobuf:"varint,6,rep,packed,name=unixtimearr,proto3" json:"unixtimearr,omitempty"`
+	RFC3339Timearr []string `protobuf:"bytes,7,rep,name=RFC3339timearr,proto3" json:"RFC3339timearr,omitempty"`
+}
+
+func (x *RouterKey) Reset() {
+	*x = RouterKey{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rarc_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RouterKey) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RouterKey) ProtoMessage() {}
+
+func (x *RouterKey) ProtoReflect() protoreflect.Message {
+	mi := &file_rarc_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RouterKey.ProtoReflect.Descriptor instead.
+func (*RouterKey) Descriptor() ([]byte, []int) {
+	return file_rarc_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *RouterKey) GetASN() string {
+	if x != nil {
+		return x.ASN
+	}
+	return ""
+}
+
+func (x *RouterKey) GetAsnum() uint32 {
+	if x != nil {
+		return x.Asnum
+	}
+	return 0
+}
+
+func (x *RouterKey) GetSKI() string {
+	if x != nil {
+		return x.SKI
+	}
+	return ""
+}
+
+func (x *RouterKey) GetPubkey() string {
+	if x != nil {
+		return x.Pubkey
+	}
+	return ""
+}
+
+func (x *RouterKey) GetTa() string {
+	if x != nil {
+		return x.Ta
+	}
+	return ""
+}
+
+func (x *RouterKey) GetUnixtimearr() []int64 {
+	if x != nil {
+		return x.Unixtimearr
+	}
+	return nil
+}
+
+func (x *RouterKey) GetRFC3339Timearr() []string {
+	if x != nil {
+		return x.RFC3339Timearr
+	}
+	return nil
+}
+
+type RouterKeyArr struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Keys []*RouterKey `protobuf:"bytes,1,rep,name=keys,proto3" json:"keys,omitempty"`
+}
+
+func (x *RouterKeyArr) Reset() {
+	*x = RouterKeyArr{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rarc_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RouterKeyArr) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RouterKeyArr) ProtoMessage() {}
+
+func (x *RouterKeyArr) ProtoReflect() protoreflect.Message {
+	mi := &file_rarc_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RouterKeyArr.ProtoReflect.Descriptor instead.
+func (*RouterKeyArr) Descriptor() ([]byte, []int) {
+	return file_rarc_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *RouterKeyArr) GetKeys() []*RouterKey {
+	if x != nil {
+		return x.Keys
+	}
+	return nil
+}
+
+// SlurmVersion is an uploaded SLURM file and when it applies.
+type SlurmVersion struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id                   string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name                 string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Uploaded             int64  `protobuf:"varint,3,opt,name=uploaded,proto3" json:"uploaded,omitempty"`
+	RFC3339Uploaded      string `protobuf:"bytes,4,opt,name=RFC3339uploaded,proto3" json:"RFC3339uploaded,omitempty"`
+	Effectivefrom        int64  `protobuf:"varint,5,opt,name=effectivefrom,proto3" json:"effectivefrom,omitempty"`
+	RFC3339Effectivefrom string `protobuf:"bytes,6,opt,name=RFC3339effectivefrom,proto3" json:"RFC3339effectivefrom,omitempty"`
+	// unset for no end
+	Effectiveto        int64  `protobuf:"varint,7,opt,name=effectiveto,proto3" json:"effectiveto,omitempty"`
+	RFC3339Effectiveto string `protobuf:"bytes,8,opt,name=RFC3339effectiveto,proto3" json:"RFC3339effectiveto,omitempty"`
+	Prefixfilters      int32  `protobuf:"varint,9,opt,name=prefixfilters,proto3" json:"prefixfilters,omitempty"`
+	Bgpsecfilters      int32  `protobuf:"varint,10,opt,name=bgpsecfilters,proto3" json:"bgpsecfilters,omitempty"`
+	Prefixassertions   int32  `protobuf:"varint,11,opt,name=prefixassertions,proto3" json:"prefixassertions,omitempty"`
+	Bgpsecassertions   int32  `protobuf:"varint,12,opt,name=bgpsecassertions,proto3" json:"bgpsecassertions,omitempty"`
+}
+
+func (x *SlurmVersion) Reset() {
+	*x = SlurmVersion{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rarc_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SlurmVersion) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SlurmVersion) ProtoMessage() {}
+
+func (x *SlurmVersion) ProtoReflect() protoreflect.Message {
+	mi := &file_rarc_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SlurmVersion.ProtoReflect.Descriptor instead.
+func (*SlurmVersion) Descriptor() ([]byte, []int) {
+	return file_rarc_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *SlurmVersion) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *SlurmVersion) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *SlurmVersion) GetUploaded() int64 {
+	if x != nil {
+		return x.Uploaded
+	}
+	return 0
+}
+
+func (x *SlurmVersion) GetRFC3339Uploaded() string {
+	if x != nil {
+		return x.RFC3339Uploaded
+	}
+	return ""
+}
+
+func (x *SlurmVersion) GetEffectivefrom() int64 {
+	if x != nil {
+		return x.Effectivefrom
+	}
+	return 0
+}
+
+func (x *SlurmVersion) GetRFC3339Effectivefrom() string {
+	if x != nil {
+		return x.RFC3339Effectivefrom
+	}
+	return ""
+}
+
+func (x *SlurmVersion) GetEffectiveto() int64 {
+	if x != nil {
+		return x.Effectiveto
+	}
+	return 0
+}
+
+func (x *SlurmVersion) GetRFC3339Effectiveto() string {
+	if x != nil {
+		return x.RFC3339Effectiveto
+	}
+	return ""
+}
+
+func (x *SlurmVersion) GetPrefixfilters() int32 {
+	if x != nil {
+		return x.Prefixfilters
+	}
+	return 0
+}
+
+func (x *SlurmVersion) GetBgpsecfilters() int32 {
+	if x != nil {
+		return x.Bgpsecfilters
+	}
+	return 0
+}
+
+func (x *SlurmVersion) GetPrefixassertions() int32 {
+	if x != nil {
+		return x.Prefixassertions
+	}
+	return 0
+}
+
+func (x *SlurmVersion) GetBgpsecassertions() int32 {
+	if x != nil {
+		return x.Bgpsecassertions
+	}
+	return 0
+}
+
+type SlurmVersions struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Versions []*SlurmVersion `protobuf:"bytes,1,rep,name=versions,proto3" json:"versions,omitempty"`
+}
+
+func (x *SlurmVersions) Reset() {
+	*x = SlurmVersions{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rarc_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SlurmVersions) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SlurmVersions) ProtoMessage() {}
+
+func (x *SlurmVersions) ProtoReflect() protoreflect.Message {
+	mi := &file_rarc_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SlurmVersions.ProtoReflect.Descriptor instead.
+func (*SlurmVersions) Descriptor() ([]byte, []int) {
+	return file_rarc_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *SlurmVersions) GetVersions() []*SlurmVersion {
+	if x != nil {
+		return x.Versions
+	}
+	return nil
+}
+
+// LooseROA is a ROA with a maxLength longer than its prefix (RFC 9319), and
+// what's known about the more-specifics it allows.
+type LooseROA struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Roa *ResultsFromDB `protobuf:"bytes,1,opt,name=roa,proto3" json:"roa,omitempty"`
+	// how many more-specific prefixes the maxLength allows
+	Permitted float64 `protobuf:"fixed64,2,opt,name=permitted,proto3" json:"permitted,omitempty"`
+	// more-specific ROAs for the same ASN inside the maxLength, the closest
+	// thing there is to knowing which more-specifics are really announced
+	Covered []*ResultsFromDB `protobuf:"bytes,3,rep,name=covered,proto3" json:"covered,omitempty"`
+	// "unused" if nothing covers it, "partial" or "complete"
+	State string `protobuf:"bytes,4,opt,name=state,proto3" json:"state,omitempty"`
+	// the minimal ROAs that would replace it, as fullprefixrange
+	Minimal []string `protobuf:"bytes,5,rep,name=minimal,proto3" json:"minimal,omitempty"`
+}
+
+func (x *LooseROA) Reset() {
+	*x = LooseROA{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rarc_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LooseROA) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LooseROA) ProtoMessage() {}
+
+func (x *LooseROA) ProtoReflect() protoreflect.Message {
+	mi := &file_rarc_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LooseROA.ProtoReflect.Descriptor instead.
+func (*LooseROA) Descriptor() ([]byte, []int) {
+	return file_rarc_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *LooseROA) GetRoa() *ResultsFromDB {
+	if x != nil {
+		return x.Roa
+	}
+	return nil
+}
+
+func (x *LooseROA) GetPermitted() float64 {
+	if x != nil {
+		return x.Permitted
+	}
+	return 0
+}
+
+func (x *LooseROA) GetCovered() []*ResultsFromDB {
+	if x != nil {
+		return x.Covered
+	}
+	return nil
+}
+
+func (x *LooseROA) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *LooseROA) GetMinimal() []string {
+	if x != nil {
+		return x.Minimal
+	}
+	return nil
+}
+
+// LooseCount is how many of a TA's or ASN's ROAs were loose at a time.
+type LooseCount struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Key         string `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Unixtime    int64  `protobuf:"varint,2,opt,name=unixtime,proto3" json:"unixtime,omitempty"`
+	RFC3339Time string `protobuf:"bytes,3,opt,name=RFC3339time,proto3" json:"RFC3339time,omitempty"`
+	Roas        int64  `protobuf:"varint,4,opt,name=roas,proto3" json:"roas,omitempty"`
+	Loose       int64  `protobuf:"varint,5,opt,name=loose,proto3" json:"loose,omitempty"`
+}
+
+func (x *LooseCount) Reset() {
+	*x = LooseCount{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rarc_proto_msgTypes[17]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LooseCount) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LooseCount) ProtoMessage() {}
+
+func (x *LooseCount) ProtoReflect() protoreflect.Message {
+	mi := &file_rarc_proto_msgTypes[17]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LooseCount.ProtoReflect.Descriptor instead.
+func (*LooseCount) Descriptor() ([]byte, []int) {
+	return file_rarc_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *LooseCount) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *LooseCount) GetUnixtime() int64 {
+	if x != nil {
+		return x.Unixtime
+	}
+	return 0
+}
+
+func (x *LooseCount) GetRFC3339Time() string {
+	if x != nil {
+		return x.RFC3339Time
+	}
+	return ""
+}
+
+func (x *LooseCount) GetRoas() int64 {
+	if x != nil {
+		return x.Roas
+	}
+	return 0
+}
+
+func (x *LooseCount) GetLoose() int64 {
+	if x != nil {
+		return x.Loose
+	}
+	return 0
+}
+
+// LooseHistory is a TA's or ASN's loose ROA counts in every update.
+type LooseHistory struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Key    string        `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Counts []*LooseCount `protobuf:"bytes,2,rep,name=counts,proto3" json:"counts,omitempty"`
+}
+
+func (x *LooseHistory) Reset() {
+	*x = LooseHistory{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rarc_proto_msgTypes[18]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LooseHistory) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LooseHistory) ProtoMessage() {}
+
+func (x *LooseHistory) ProtoReflect() protoreflect.Message {
+	mi := &file_rarc_proto_msgTypes[18]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LooseHistory.ProtoReflect.Descriptor instead.
+func (*LooseHistory) Descriptor() ([]byte, []int) {
+	return file_rarc_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *LooseHistory) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *LooseHistory) GetCounts() []*LooseCount {
+	if x != nil {
+		return x.Counts
+	}
+	return nil
+}
+
+// MaxLenReport is the RFC 9319 maxLength report for a snapshot.
+type MaxLenReport struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Unixtime    int64         `protobuf:"varint,1,opt,name=unixtime,proto3" json:"unixtime,omitempty"`
+	RFC3339Time string        `protobuf:"bytes,2,opt,name=RFC3339time,proto3" json:"RFC3339time,omitempty"`
+	Roas        int64         `protobuf:"varint,3,opt,name=roas,proto3" json:"roas,omitempty"`
+	Loose       int64         `protobuf:"varint,4,opt,name=loose,proto3" json:"loose,omitempty"`
+	Tas         []*LooseCount `protobuf:"bytes,5,rep,name=tas,proto3" json:"tas,omitempty"`
+	// ASNs with loose ROAs, most first
+	Asns []*LooseCount `protobuf:"bytes,6,rep,name=asns,proto3" json:"asns,omitempty"`
+	// only filled in for one ASN, or when asked for
+	Looseroas []*LooseROA     `protobuf:"bytes,7,rep,name=looseroas,proto3" json:"looseroas,omitempty"`
+	History   []*LooseHistory `protobuf:"bytes,8,rep,name=history,proto3" json:"history,omitempty"`
+}
+
+func (x *MaxLenReport) Reset() {
+	*x = MaxLenReport{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rarc_proto_msgTypes[19]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MaxLenReport) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MaxLenReport) ProtoMessage() {}
+
+func (x *MaxLenReport) ProtoReflect() protoreflect.Message {
+	mi := &file_rarc_proto_msgTypes[19]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MaxLenReport.ProtoReflect.Descriptor instead.
+func (*MaxLenReport) Descriptor() ([]byte, []int) {
+	return file_rarc_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *MaxLenReport) GetUnixtime() int64 {
+	if x != nil {
+		return x.Unixtime
+	}
+	return 0
+}
+
+func (x *MaxLenReport) GetRFC3339Time() string {
+	if x != nil {
+		return x.RFC3339Time
+	}
+	return ""
+}
+
+func (x *MaxLenReport) GetRoas() int64 {
+	if x != nil {
+		return x.Roas
+	}
+	return 0
+}
+
+func (x *MaxLenReport) GetLoose() int64 {
+	if x != nil {
+		return x.Loose
+	}
+	return 0
+}
+
+func (x *MaxLenReport) GetTas() []*LooseCount {
+	if x != nil {
+		return x.Tas
+	}
+	return nil
+}
+
+func (x *MaxLenReport) GetAsns() []*LooseCount {
+	if x != nil {
+		return x.Asns
+	}
+	return nil
+}
+
+func (x *MaxLenReport) GetLooseroas() []*LooseROA {
+	if x != nil {
+		return x.Looseroas
+	}
+	return nil
+}
+
+func (x *MaxLenReport) GetHistory() []*LooseHistory {
+	if x != nil {
+		return x.History
+	}
+	return nil
+}
+
+// Event is a suspicious ROA change spotted at ingest.
+type Event struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// the update it was spotted in
+	Unixtime    int64  `protobuf:"varint,2,opt,name=unixtime,proto3" json:"unixtime,omitempty"`
+	RFC3339Time string `protobuf:"bytes,3,opt,name=RFC3339time,proto3" json:"RFC3339time,omitempty"`
+	// "new_origin", "as0_added" or "as0_removed"
+	Kind string `protobuf:"bytes,4,opt,name=kind,proto3" json:"kind,omitempty"`
+	// "low", "medium" or "high"
+	Severity string `protobuf:"bytes,5,opt,name=severity,proto3" json:"severity,omitempty"`
+	Prefix   string `protobuf:"bytes,6,opt,name=prefix,proto3" json:"prefix,omitempty"`
+	ASN      string `protobuf:"bytes,7,opt,name=ASN,proto3" json:"ASN,omitempty"`
+	Asnum    uint32 `protobuf:"varint,8,opt,name=asnum,proto3" json:"asnum,omitempty"`
+	// other ASNs that have had ROAs for the prefix
+	PreviousASNs []string `protobuf:"bytes,9,rep,name=previousASNs,proto3" json:"previousASNs,omitempty"`
+	Tas          []string `protobuf:"bytes,10,rep,name=tas,proto3" json:"tas,omitempty"`
+	Detail       string   `protobuf:"bytes,11,opt,name=detail,proto3" json:"detail,omitempty"`
+}
+
+func (x *Event) Reset() {
+	*x = Event{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rarc_proto_msgTypes[20]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Event) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Event) ProtoMessage() {}
+
+func (x *Event) ProtoReflect() protoreflect.Message {
+	mi := &file_rarc_proto_msgTypes[20]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Event.ProtoReflect.Descriptor instead.
+func (*Event) Descriptor() ([]byte, []int) {
+	return file_rarc_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *Event) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Event) GetUnixtime() int64 {
+	if x != nil {
+		return x.Unixtime
+	}
+	return 0
+}
+
+func (x *Event) GetRFC3339Time() string {
+	if x != nil {
+		return x.RFC3339Time
+	}
+	return ""
+}
+
+func (x *Event) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *Event) GetSeverity() string {
+	if x != nil {
+		return x.Severity
+	}
+	return ""
+}
+
+func (x *Event) GetPrefix() string {
+	if x != nil {
+		return x.Prefix
+	}
+	return ""
+}
+
+func (x *Event) GetASN() string {
+	if x != nil {
+		return x.ASN
+	}
+	return ""
+}
+
+func (x *Event) GetAsnum() uint32 {
+	if x != nil {
+		return x.Asnum
+	}
+	return 0
+}
+
+func (x *Event) GetPreviousASNs() []string {
+	if x != nil {
+		return x.PreviousASNs
+	}
+	return nil
+}
+
+func (x *Event) GetTas() []string {
+	if x != nil {
+		return x.Tas
+	}
+	return nil
+}
+
+func (x *Event) GetDetail() string {
+	if x != nil {
+		return x.Detail
+	}
+	return ""
+}
+
+type Events struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Events []*Event `protobuf:"bytes,1,rep,name=events,proto3" json:"events,omitempty"`
+}
+
+func (x *Events) Reset() {
+	*x = Events{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rarc_proto_msgTypes[21]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Events) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Events) ProtoMessage() {}
+
+func (x *Events) ProtoReflect() protoreflect.Message {
+	mi := &file_rarc_proto_msgTypes[21]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Events.ProtoReflect.Descriptor instead.
+func (*Events) Descriptor() ([]byte, []int) {
+	return file_rarc_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *Events) GetEvents() []*Event {
+	if x != nil {
+		return x.Events
+	}
+	return nil
+}
+
+// Watchlist is a set of ASNs, prefixes and TAs to send a webhook about when
+// an update changes any of their ROAs.
+type Watchlist struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id       string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name     string   `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Asns     []string `protobuf:"bytes,3,rep,name=asns,proto3" json:"asns,omitempty"`
+	Prefixes []string `protobuf:"bytes,4,rep,name=prefixes,proto3" json:"prefixes,omitempty"`
+	Tas      []string `protobuf:"bytes,5,rep,name=tas,proto3" json:"tas,omitempty"`
+	// where the webhook is POSTed
+	Url string `protobuf:"bytes,6,opt,name=url,proto3" json:"url,omitempty"`
+	// the HMAC key webhooks are signed with, only shown when it's made
+	Secret         string `protobuf:"bytes,7,opt,name=secret,proto3" json:"secret,omitempty"`
+	Created        int64  `protobuf:"varint,8,opt,name=created,proto3" json:"created,omitempty"`
+	RFC3339Created string `protobuf:"bytes,9,opt,name=RFC3339created,proto3" json:"RFC3339created,omitempty"`
+}
+
+func (x *Watchlist) Reset() {
+	*x = Watchlist{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rarc_proto_msgTypes[22]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Watchlist) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Watchlist) ProtoMessage() {}
+
+func (x *Watchlist) ProtoReflect() protoreflect.Message {
+	mi := &file_rarc_proto_msgTypes[22]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Watchlist.ProtoReflect.Descriptor instead.
+func (*Watchlist) Descriptor() ([]byte, []int) {
+	return file_rarc_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *Watchlist) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Watchlist) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Watchlist) GetAsns() []string {
+	if x != nil {
+		return x.Asns
+	}
+	return nil
+}
+
+func (x *Watchlist) GetPrefixes() []string {
+	if x != nil {
+		return x.Prefixes
+	}
+	return nil
+}
+
+func (x *Watchlist) GetTas() []string {
+	if x != nil {
+		return x.Tas
+	}
+	return nil
+}
+
+func (x *Watchlist) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *Watchlist) GetSecret() string {
+	if x != nil {
+		return x.Secret
+	}
+	return ""
+}
+
+func (x *Watchlist) GetCreated() int64 {
+	if x != nil {
+		return x.Created
+	}
+	return 0
+}
+
+func (x *Watchlist) GetRFC3339Created() string {
+	if x != nil {
+		return x.RFC3339Created
+	}
+	return ""
+}
+
+type Watchlists struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Watchlists []*Watchlist `protobuf:"bytes,1,rep,name=watchlists,proto3" json:"watchlists,omitempty"`
+}
+
+func (x *Watchlists) Reset() {
+	*x = Watchlists{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rarc_proto_msgTypes[23]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Watchlists) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Watchlists) ProtoMessage() {}
+
+func (x *Watchlists) ProtoReflect() protoreflect.Message {
+	mi := &file_rarc_proto_msgTypes[23]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Watchlists.ProtoReflect.Descriptor instead.
+func (*Watchlists) Descriptor() ([]byte, []int) {
+	return file_rarc_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *Watchlists) GetWatchlists() []*Watchlist {
+	if x != nil {
+		return x.Watchlists
+	}
+	return nil
+}
+
+// WatchNotification is the webhook body, the ROAs an update added and removed
+// that a watchlist covers.
+type WatchNotification struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Watchlist string `protobuf:"bytes,1,opt,name=watchlist,proto3" json:"watchlist,omitempty"`
+	Name      string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	// the update's ID in the runs table
+	Run         string           `protobuf:"bytes,3,opt,name=run,proto3" json:"run,omitempty"`
+	Unixtime    int64            `protobuf:"varint,4,opt,name=unixtime,proto3" json:"unixtime,omitempty"`
+	RFC3339Time string           `protobuf:"bytes,5,opt,name=RFC3339time,proto3" json:"RFC3339time,omitempty"`
+	Added       []*ResultsFromDB `protobuf:"bytes,6,rep,name=added,proto3" json:"added,omitempty"`
+	Removed     []*ResultsFromDB `protobuf:"bytes,7,rep,name=removed,proto3" json:"removed,omitempty"`
+	// changes for TAs that looked down in this update or the one before.
+	// They're most likely the outage starting or ending, so they're kept
+	// out of added and removed.
+	Outageadded   []*ResultsFromDB `protobuf:"bytes,8,rep,name=outageadded,proto3" json:"outageadded,omitempty"`
+	Outageremoved []*ResultsFromDB `protobuf:"bytes,9,rep,name=outageremoved,proto3" json:"outageremoved,omitempty"`
+}
+
+func (x *WatchNotification) Reset() {
+	*x = WatchNotification{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rarc_proto_msgTypes[24]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WatchNotification) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchNotification) ProtoMessage() {}
+
+func (x *WatchNotification) ProtoReflect() protoreflect.Message {
+	mi := &file_rarc_proto_msgTypes[24]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchNotification.ProtoReflect.Descriptor instead.
+func (*WatchNotification) Descriptor() ([]byte, []int) {
+	return file_rarc_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *WatchNotification) GetWatchlist() string {
+	if x != nil {
+		return x.Watchlist
+	}
+	return ""
+}
+
+func (x *WatchNotification) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *WatchNotification) GetRun() string {
+	if x != nil {
+		return x.Run
+	}
+	return ""
+}
+
+func (x *WatchNotification) GetUnixtime() int64 {
+	if x != nil {
+		return x.Unixtime
+	}
+	return 0
+}
+
+func (x *WatchNotification) GetRFC3339Time() string {
+	if x != nil {
+		return x.RFC3339Time
+	}
+	return ""
+}
+
+func (x *WatchNotification) GetAdded() []*ResultsFromDB {
+	if x != nil {
+		return x.Added
+	}
+	return nil
+}
+
+func (x *WatchNotification) GetRemoved() []*ResultsFromDB {
+	if x != nil {
+		return x.Removed
+	}
+	return nil
+}
+
+func (x *WatchNotification) GetOutageadded() []*ResultsFromDB {
+	if x != nil {
+		return x.Outageadded
+	}
+	return nil
+}
+
+func (x *WatchNotification) GetOutageremoved() []*ResultsFromDB {
+	if x != nil {
+		return x.Outageremoved
+	}
+	return nil
+}
+
+// Changes is the ROAs an update added and removed, as sent by /api/stream.
+type Changes struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// the update's ID in the runs table, also the SSE event ID
+	Run         string           `protobuf:"bytes,1,opt,name=run,proto3" json:"run,omitempty"`
+	Unixtime    int64            `protobuf:"varint,2,opt,name=unixtime,proto3" json:"unixtime,omitempty"`
+	RFC3339Time string           `protobuf:"bytes,3,opt,name=RFC3339time,proto3" json:"RFC3339time,omitempty"`
+	Added       []*ResultsFromDB `protobuf:"bytes,4,rep,name=added,proto3" json:"added,omitempty"`
+	Removed     []*ResultsFromDB `protobuf:"bytes,5,rep,name=removed,proto3" json:"removed,omitempty"`
+	// like WatchNotification's
+	Outageadded   []*ResultsFromDB `protobuf:"bytes,6,rep,name=outageadded,proto3" json:"outageadded,omitempty"`
+	Outageremoved []*ResultsFromDB `protobuf:"bytes,7,rep,name=outageremoved,proto3" json:"outageremoved,omitempty"`
+}
+
+func (x *Changes) Reset() {
+	*x = Changes{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rarc_proto_msgTypes[25]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Changes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Changes) ProtoMessage() {}
+
+func (x *Changes) ProtoReflect() protoreflect.Message {
+	mi := &file_rarc_proto_msgTypes[25]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Changes.ProtoReflect.Descriptor instead.
+func (*Changes) Descriptor() ([]byte, []int) {
+	return file_rarc_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *Changes) GetRun() string {
+	if x != nil {
+		return x.Run
+	}
+	return ""
+}
+
+func (x *Changes) GetUnixtime() int64 {
+	if x != nil {
+		return x.Unixtime
+	}
+	return 0
+}
+
+func (x *Changes) GetRFC3339Time() string {
+	if x != nil {
+		return x.RFC3339Time
+	}
+	return ""
+}
+
+func (x *Changes) GetAdded() []*ResultsFromDB {
+	if x != nil {
+		return x.Added
+	}
+	return nil
+}
+
+func (x *Changes) GetRemoved() []*ResultsFromDB {
+	if x != nil {
+		return x.Removed
+	}
+	return nil
+}
+
+func (x *Changes) GetOutageadded() []*ResultsFromDB {
+	if x != nil {
+		return x.Outageadded
+	}
+	return nil
+}
+
+func (x *Changes) GetOutageremoved() []*ResultsFromDB {
+	if x != nil {
+		return x.Outageremoved
+	}
+	return nil
+}
+
+// Subscriber gets an email digest of ROA changes for some ASNs, prefixes
+// and TAs.
+type Subscriber struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id       string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name     string   `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Email    string   `protobuf:"bytes,3,opt,name=email,proto3" json:"email,omitempty"`
+	Asns     []string `protobuf:"bytes,4,rep,name=asns,proto3" json:"asns,omitempty"`
+	Prefixes []string `protobuf:"bytes,5,rep,name=prefixes,proto3" json:"prefixes,omitempty"`
+	Tas      []string `protobuf:"bytes,6,rep,name=tas,proto3" json:"tas,omitempty"`
+	// "daily" or "weekly"
+	Frequency      string `protobuf:"bytes,7,opt,name=frequency,proto3" json:"frequency,omitempty"`
+	Created        int64  `protobuf:"varint,8,opt,name=created,proto3" json:"created,omitempty"`
+	RFC3339Created string `protobuf:"bytes,9,opt,name=RFC3339created,proto3" json:"RFC3339created,omitempty"`
+	// when the last digest covered up to
+	Lastsent        int64  `protobuf:"varint,10,opt,name=lastsent,proto3" json:"lastsent,omitempty"`
+	RFC3339Lastsent string `protobuf:"bytes,11,opt,name=RFC3339lastsent,proto3" json:"RFC3339lastsent,omitempty"`
+}
+
+func (x *Subscriber) Reset() {
+	*x = Subscriber{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rarc_proto_msgTypes[26]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Subscriber) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Subscriber) ProtoMessage() {}
+
+func (x *Subscriber) ProtoReflect() protoreflect.Message {
+	mi := &file_rarc_proto_msgTypes[26]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Subscriber.ProtoReflect.Descriptor instead.
+func (*Subscriber) Descriptor() ([]byte, []int) {
+	return file_rarc_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *Subscriber) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Subscriber) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Subscriber) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *Subscriber) GetAsns() []string {
+	if x != nil {
+		return x.Asns
+	}
+	return nil
+}
+
+func (x *Subscriber) GetPrefixes() []string {
+	if x != nil {
+		return x.Prefixes
+	}
+	return nil
+}
+
+func (x *Subscriber) GetTas() []string {
+	if x != nil {
+		return x.Tas
+	}
+	return nil
+}
+
+func (x *Subscriber) GetFrequency() string {
+	if x != nil {
+		return x.Frequency
+	}
+	return ""
+}
+
+func (x *Subscriber) GetCreated() int64 {
+	if x != nil {
+		return x.Created
+	}
+	return 0
+}
+
+func (x *Subscriber) GetRFC3339Created() string {
+	if x != nil {
+		return x.RFC3339Created
+	}
+	return ""
+}
+
+func (x *Subscriber) GetLastsent() int64 {
+	if x != nil {
+		return x.Lastsent
+	}
+	return 0
+}
+
+func (x *Subscriber) GetRFC3339Lastsent() string {
+	if x != nil {
+		return x.RFC3339Lastsent
+	}
+	return ""
+}
+
+type Subscribers struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Subscribers []*Subscriber `protobuf:"bytes,1,rep,name=subscribers,proto3" json:"subscribers,omitempty"`
+}
+
+func (x *Subscribers) Reset() {
+	*x = Subscribers{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rarc_proto_msgTypes[27]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Subscribers) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Subscribers) ProtoMessage() {}
+
+func (x *Subscribers) ProtoReflect() protoreflect.Message {
+	mi := &file_rarc_proto_msgTypes[27]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Subscribers.ProtoReflect.Descriptor instead.
+func (*Subscribers) Descriptor() ([]byte, []int) {
+	return file_rarc_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *Subscribers) GetSubscribers() []*Subscriber {
+	if x != nil {
+		return x.Subscribers
+	}
+	return nil
+}
+
+// Lifespan is when a ROA was around. It counts as present from an update it
+// showed up in until the first update it was missing from. If it's still
+// there that's now, but no more than an update interval after the latest
+// update, so it doesn't keep growing if updates stop. It's only there when
+// the index is loaded.
+type Lifespan struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Firstseen        int64  `protobuf:"varint,1,opt,name=firstseen,proto3" json:"firstseen,omitempty"`
+	RFC3339Firstseen string `protobuf:"bytes,2,opt,name=RFC3339firstseen,proto3" json:"RFC3339firstseen,omitempty"`
+	Lastseen         int64  `protobuf:"varint,3,opt,name=lastseen,proto3" json:"lastseen,omitempty"`
+	RFC3339Lastseen  string `protobuf:"bytes,4,opt,name=RFC3339lastseen,proto3" json:"RFC3339lastseen,omitempty"`
+	// seconds
+	Present int64 `protobuf:"varint,5,opt,name=present,proto3" json:"present,omitempty"`
+	// how many times it went missing and came back
+	Gaps int32 `protobuf:"varint,6,opt,name=gaps,proto3" json:"gaps,omitempty"`
+	// seconds
+	Longestgap int64 `protobuf:"varint,7,opt,name=longestgap,proto3" json:"longestgap,omitempty"`
+	// in the latest update
+	Current bool `protobuf:"varint,8,opt,name=current,proto3" json:"current,omitempty"`
+}
+
+func (x *Lifespan) Reset() {
+	*x = Lifespan{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rarc_proto_msgTypes[28]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Lifespan) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Lifespan) ProtoMessage() {}
+
+func (x *Lifespan) ProtoReflect() protoreflect.Message {
+	mi := &file_rarc_proto_msgTypes[28]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Lifespan.ProtoReflect.Descriptor instead.
+func (*Lifespan) Descriptor() ([]byte, []int) {
+	return file_rarc_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *Lifespan) GetFirstseen() int64 {
+	if x != nil {
+		return x.Firstseen
+	}
+	return 0
+}
+
+func (x *Lifespan) GetRFC3339Firstseen() string {
+	if x != nil {
+		return x.RFC3339Firstseen
+	}
+	return ""
+}
+
+func (x *Lifespan) GetLastseen() int64 {
+	if x != nil {
+		return x.Lastseen
+	}
+	return 0
+}
+
+func (x *Lifespan) GetRFC3339Lastseen() string {
+	if x != nil {
+		return x.RFC3339Lastseen
+	}
+	return ""
+}
+
+func (x *Lifespan) GetPresent() int64 {
+	if x != nil {
+		return x.Present
+	}
+	return 0
+}
+
+func (x *Lifespan) GetGaps() int32 {
+	if x != nil {
+		return x.Gaps
+	}
+	return 0
+}
+
+func (x *Lifespan) GetLongestgap() int64 {
+	if x != nil {
+		return x.Longestgap
+	}
+	return 0
+}
+
+func (x *Lifespan) GetCurrent() bool {
+	if x != nil {
+		return x.Current
+	}
+	return false
+}
+
+// ChurnCount is how many ROAs were added and removed on a day (UTC).
+type ChurnCount struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Unixtime    int64  `protobuf:"varint,1,opt,name=unixtime,proto3" json:"unixtime,omitempty"`
+	RFC3339Time string `protobuf:"bytes,2,opt,name=RFC3339time,proto3" json:"RFC3339time,omitempty"`
+	Added       int64  `protobuf:"varint,3,opt,name=added,proto3" json:"added,omitempty"`
+	Removed     int64  `protobuf:"varint,4,opt,name=removed,proto3" json:"removed,omitempty"`
+}
+
+func (x *ChurnCount) Reset() {
+	*x = ChurnCount{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rarc_proto_msgTypes[29]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ChurnCount) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChurnCount) ProtoMessage() {}
+
+func (x *ChurnCount) ProtoReflect() protoreflect.Message {
+	mi := &file_rarc_proto_msgTypes[29]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChurnCount.ProtoReflect.Descriptor instead.
+func (*ChurnCount) Descriptor() ([]byte, []int) {
+	return file_rarc_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *ChurnCount) GetUnixtime() int64 {
+	if x != nil {
+		return x.Unixtime
+	}
+	return 0
+}
+
+func (x *ChurnCount) GetRFC3339Time() string {
+	if x != nil {
+		return x.RFC3339Time
+	}
+	return ""
+}
+
+func (x *ChurnCount) GetAdded() int64 {
+	if x != nil {
+		return x.Added
+	}
+	return 0
+}
+
+func (x *ChurnCount) GetRemoved() int64 {
+	if x != nil {
+		return x.Removed
+	}
+	return 0
+}
+
+// ChurnSeries is a TA's churn every day there was an update.
+type ChurnSeries struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Ta string `protobuf:"bytes,1,opt,name=ta,proto3" json:"ta,omitempty"`
+	// totals over the days
+	Added   int64         `protobuf:"varint,2,opt,name=added,proto3" json:"added,omitempty"`
+	Removed int64         `protobuf:"varint,3,opt,name=removed,proto3" json:"removed,omitempty"`
+	Days    []*ChurnCount `protobuf:"bytes,4,rep,name=days,proto3" json:"days,omitempty"`
+}
+
+func (x *ChurnSeries) Reset() {
+	*x = ChurnSeries{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rarc_proto_msgTypes[30]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ChurnSeries) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChurnSeries) ProtoMessage() {}
+
+func (x *ChurnSeries) ProtoReflect() protoreflect.Message {
+	mi := &file_rarc_proto_msgTypes[30]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChurnSeries.ProtoReflect.Descriptor instead.
+func (*ChurnSeries) Descriptor() ([]byte, []int) {
+	return file_rarc_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *ChurnSeries) GetTa() string {
+	if x != nil {
+		return x.Ta
+	}
+	return ""
+}
+
+func (x *ChurnSeries) GetAdded() int64 {
+	if x != nil {
+		return x.Added
+	}
+	return 0
+}
+
+func (x *ChurnSeries) GetRemoved() int64 {
+	if x != nil {
+		return x.Removed
+	}
+	return 0
+}
+
+func (x *ChurnSeries) GetDays() []*ChurnCount {
+	if x != nil {
+		return x.Days
+	}
+	return nil
+}
+
+type Churn struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Tas []*ChurnSeries `protobuf:"bytes,1,rep,name=tas,proto3" json:"tas,omitempty"`
+}
+
+func (x *Churn) Reset() {
+	*x = Churn{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rarc_proto_msgTypes[31]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Churn) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Churn) ProtoMessage() {}
+
+func (x *Churn) ProtoReflect() protoreflect.Message {
+	mi := &file_rarc_proto_msgTypes[31]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Churn.ProtoReflect.Descriptor instead.
+func (*Churn) Descriptor() ([]byte, []int) {
+	return file_rarc_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *Churn) GetTas() []*ChurnSeries {
+	if x != nil {
+		return x.Tas
+	}
+	return nil
+}
+
+var File_rarc_proto protoreflect.FileDescriptor
+
+var file_rarc_proto_rawDesc = []byte{
+	0x0a, 0x0a, 0x72, 0x61, 0x72, 0x63, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x09, 0x72, 0x61,
+	0x72, 0x63, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xf5, 0x03, 0x0a, 0x0d, 0x52, 0x65, 0x73, 0x75,
+	0x6c, 0x74, 0x73, 0x46, 0x72, 0x6f, 0x6d, 0x44, 0x42, 0x12, 0x10, 0x0a, 0x03, 0x41, 0x53, 0x4e,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x41, 0x53, 0x4e, 0x12, 0x16, 0x0a, 0x06, 0x70,
+	0x72, 0x65, 0x66, 0x69, 0x78, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x70, 0x72, 0x65,
+	0x66, 0x69, 0x78, 0x12, 0x16, 0x0a, 0x06, 0x6d, 0x61, 0x78, 0x6c, 0x65, 0x6e, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x06, 0x6d, 0x61, 0x78, 0x6c, 0x65, 0x6e, 0x12, 0x0e, 0x0a, 0x02, 0x74,
+	0x61, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x74, 0x61, 0x12, 0x12, 0x0a, 0x04, 0x6d,
+	0x61, 0x73, 0x6b, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x6d, 0x61, 0x73, 0x6b, 0x12,
+	0x1e, 0x0a, 0x0a, 0x66, 0x75, 0x6c, 0x6c, 0x70, 0x72, 0x65, 0x66, 0x69, 0x78, 0x18, 0x07, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0a, 0x66, 0x75, 0x6c, 0x6c, 0x70, 0x72, 0x65, 0x66, 0x69, 0x78, 0x12,
+	0x28, 0x0a, 0x0f, 0x66, 0x75, 0x6c, 0x6c, 0x70, 0x72, 0x65, 0x66, 0x69, 0x78, 0x72, 0x61, 0x6e,
+	0x67, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x66, 0x75, 0x6c, 0x6c, 0x70, 0x72,
+	0x65, 0x66, 0x69, 0x78, 0x72, 0x61, 0x6e, 0x67, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x75, 0x6e, 0x69,
+	0x78, 0x74, 0x69, 0x6d, 0x65, 0x61, 0x72, 0x72, 0x18, 0x09, 0x20, 0x03, 0x28, 0x03, 0x52, 0x0b,
+	0x75, 0x6e, 0x69, 0x78, 0x74, 0x69, 0x6d, 0x65, 0x61, 0x72, 0x72, 0x12, 0x26, 0x0a, 0x0e, 0x52,
+	0x46, 0x43, 0x33, 0x33, 0x33, 0x39, 0x74, 0x69, 0x6d, 0x65, 0x61, 0x72, 0x72, 0x18, 0x0a, 0x20,
+	0x03, 0x28, 0x09, 0x52, 0x0e, 0x52, 0x46, 0x43, 0x33, 0x33, 0x33, 0x39, 0x74, 0x69, 0x6d, 0x65,
+	0x61, 0x72, 0x72, 0x12, 0x32, 0x0a, 0x14, 0x67, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x65, 0x64,
+	0x75, 0x6e, 0x69, 0x78, 0x74, 0x69, 0x6d, 0x65, 0x61, 0x72, 0x72, 0x18, 0x0b, 0x20, 0x03, 0x28,
+	0x03, 0x52, 0x14, 0x67, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x65, 0x64, 0x75, 0x6e, 0x69, 0x78,
+	0x74, 0x69, 0x6d, 0x65, 0x61, 0x72, 0x72, 0x12, 0x38, 0x0a, 0x17, 0x52, 0x46, 0x43, 0x33, 0x33,
+	0x33, 0x39, 0x67, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x65, 0x64, 0x74, 0x69, 0x6d, 0x65, 0x61,
+	0x72, 0x72, 0x18, 0x0c, 0x20, 0x03, 0x28, 0x09, 0x52, 0x17, 0x52, 0x46, 0x43, 0x33, 0x33, 0x33,
+	0x39, 0x67, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x65, 0x64, 0x74, 0x69, 0x6d, 0x65, 0x61, 0x72,
+	0x72, 0x12, 0x14, 0x0a, 0x05, 0x61, 0x73, 0x6e, 0x75, 0x6d, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x0d,
+	0x52, 0x05, 0x61, 0x73, 0x6e, 0x75, 0x6d, 0x12, 0x35, 0x0a, 0x0a, 0x70, 0x72, 0x6f, 0x76, 0x65,
+	0x6e, 0x61, 0x6e, 0x63, 0x65, 0x18, 0x0e, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x72, 0x61,
+	0x72, 0x63, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x50, 0x72, 0x6f, 0x76, 0x65, 0x6e, 0x61, 0x6e,
+	0x63, 0x65, 0x52, 0x0a, 0x70, 0x72, 0x6f, 0x76, 0x65, 0x6e, 0x61, 0x6e, 0x63, 0x65, 0x12, 0x2f,
+	0x0a, 0x08, 0x6c, 0x69, 0x66, 0x65, 0x73, 0x70, 0x61, 0x6e, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x13, 0x2e, 0x72, 0x61, 0x72, 0x63, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4c, 0x69, 0x66,
+	0x65, 0x73, 0x70, 0x61, 0x6e, 0x52, 0x08, 0x6c, 0x69, 0x66, 0x65, 0x73, 0x70, 0x61, 0x6e, 0x22,
+	0x8a, 0x02, 0x0a, 0x0a, 0x50, 0x72, 0x6f, 0x76, 0x65, 0x6e, 0x61, 0x6e, 0x63, 0x65, 0x12, 0x10,
+	0x0a, 0x03, 0x75, 0x72, 0x69, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x75, 0x72, 0x69,
+	0x12, 0x10, 0x0a, 0x03, 0x53, 0x4b, 0x49, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x53,
+	0x4b, 0x49, 0x12, 0x1c, 0x0a, 0x09, 0x6e, 0x6f, 0x74, 0x62, 0x65, 0x66, 0x6f, 0x72, 0x65, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x6e, 0x6f, 0x74, 0x62, 0x65, 0x66, 0x6f, 0x72, 0x65,
+	0x12, 0x2a, 0x0a, 0x10, 0x52, 0x46, 0x43, 0x33, 0x33, 0x33, 0x39, 0x6e, 0x6f, 0x74, 0x62, 0x65,
+	0x66, 0x6f, 0x72, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x10, 0x52, 0x46, 0x43, 0x33,
+	0x33, 0x33, 0x39, 0x6e, 0x6f, 0x74, 0x62, 0x65, 0x66, 0x6f, 0x72, 0x65, 0x12, 0x1a, 0x0a, 0x08,
+	0x6e, 0x6f, 0x74, 0x61, 0x66, 0x74, 0x65, 0x72, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08,
+	0x6e, 0x6f, 0x74, 0x61, 0x66, 0x74, 0x65, 0x72, 0x12, 0x28, 0x0a, 0x0f, 0x52, 0x46, 0x43, 0x33,
+	0x33, 0x33, 0x39, 0x6e, 0x6f, 0x74, 0x61, 0x66, 0x74, 0x65, 0x72, 0x18, 0x06, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0f, 0x52, 0x46, 0x43, 0x33, 0x33, 0x33, 0x39, 0x6e, 0x6f, 0x74, 0x61, 0x66, 0x74,
+	0x65, 0x72, 0x12, 0x20, 0x0a, 0x0b, 0x75, 0x6e, 0x69, 0x78, 0x74, 0x69, 0x6d, 0x65, 0x61, 0x72,
+	0x72, 0x18, 0x07, 0x20, 0x03, 0x28, 0x03, 0x52, 0x0b, 0x75, 0x6e, 0x69, 0x78, 0x74, 0x69, 0x6d,
+	0x65, 0x61, 0x72, 0x72, 0x12, 0x26, 0x0a, 0x0e, 0x52, 0x46, 0x43, 0x33, 0x33, 0x33, 0x39, 0x74,
+	0x69, 0x6d, 0x65, 0x61, 0x72, 0x72, 0x18, 0x08, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0e, 0x52, 0x46,
+	0x43, 0x33, 0x33, 0x33, 0x39, 0x74, 0x69, 0x6d, 0x65, 0x61, 0x72, 0x72, 0x22, 0xda, 0x01, 0x0a,
+	0x14, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x46, 0x72, 0x6f, 0x6d, 0x44, 0x42, 0x52, 0x46,
+	0x43, 0x33, 0x33, 0x33, 0x39, 0x12, 0x10, 0x0a, 0x03, 0x41, 0x53, 0x4e, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x03, 0x41, 0x53, 0x4e, 0x12, 0x16, 0x0a, 0x06, 0x70, 0x72, 0x65, 0x66, 0x69,
+	0x78, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x70, 0x72, 0x65, 0x66, 0x69, 0x78, 0x12,
+	0x16, 0x0a, 0x06, 0x6d, 0x61, 0x78, 0x6c, 0x65, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x06, 0x6d, 0x61, 0x78, 0x6c, 0x65, 0x6e, 0x12, 0x0e, 0x0a, 0x02, 0x74, 0x61, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x02, 0x74, 0x61, 0x12, 0x12, 0x0a, 0x04, 0x6d, 0x61, 0x73, 0x6b, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x6d, 0x61, 0x73, 0x6b, 0x12, 0x12, 0x0a, 0x04, 0x74,
+	0x69, 0x6d, 0x65, 0x18, 0x06, 0x20, 0x03, 0x28, 0x09, 0x52, 0x04, 0x74, 0x69, 0x6d, 0x65, 0x12,
+	0x1e, 0x0a, 0x0a, 0x66, 0x75, 0x6c, 0x6c, 0x70, 0x72, 0x65, 0x66, 0x69, 0x78, 0x18, 0x07, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0a, 0x66, 0x75, 0x6c, 0x6c, 0x70, 0x72, 0x65, 0x66, 0x69, 0x78, 0x12,
+	0x28, 0x0a, 0x0f, 0x66, 0x75, 0x6c, 0x6c, 0x70, 0x72, 0x65, 0x66, 0x69, 0x78, 0x72, 0x61, 0x6e,
+	0x67, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x66, 0x75, 0x6c, 0x6c, 0x70, 0x72,
+	0x65, 0x66, 0x69, 0x78, 0x72, 0x61, 0x6e, 0x67, 0x65, 0x22, 0x3f, 0x0a, 0x09, 0x52, 0x65, 0x73,
+	0x75, 0x6c, 0x74, 0x41, 0x72, 0x72, 0x12, 0x32, 0x0a, 0x07, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74,
+	0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x72, 0x61, 0x72, 0x63, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x2e, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x46, 0x72, 0x6f, 0x6d, 0x44,
+	0x42, 0x52, 0x07, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x22, 0xf4, 0x01, 0x0a, 0x08, 0x56,
+	0x61, 0x6c, 0x69, 0x64, 0x69, 0x74, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x12, 0x16, 0x0a,
+	0x06, 0x70, 0x72, 0x65, 0x66, 0x69, 0x78, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x70,
+	0x72, 0x65, 0x66, 0x69, 0x78, 0x12, 0x10, 0x0a, 0x03, 0x41, 0x53, 0x4e, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x03, 0x41, 0x53, 0x4e, 0x12, 0x1a, 0x0a, 0x08, 0x75, 0x6e, 0x69, 0x78, 0x74,
+	0x69, 0x6d, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x75, 0x6e, 0x69, 0x78, 0x74,
+	0x69, 0x6d, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x52, 0x46, 0x43, 0x33, 0x33, 0x33, 0x39, 0x74, 0x69,
+	0x6d, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x52, 0x46, 0x43, 0x33, 0x33, 0x33,
+	0x39, 0x74, 0x69, 0x6d, 0x65, 0x12, 0x32, 0x0a, 0x07, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x65, 0x64,
+	0x18, 0x06, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x72, 0x61, 0x72, 0x63, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x2e, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x46, 0x72, 0x6f, 0x6d, 0x44, 0x42,
+	0x52, 0x07, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x65, 0x64, 0x12, 0x36, 0x0a, 0x09, 0x75, 0x6e, 0x6d,
+	0x61, 0x74, 0x63, 0x68, 0x65, 0x64, 0x18, 0x07, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x72,
+	0x61, 0x72, 0x63, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73,
+	0x46, 0x72, 0x6f, 0x6d, 0x44, 0x42, 0x52, 0x09, 0x75, 0x6e, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x65,
+	0x64, 0x22, 0xfa, 0x01, 0x0a, 0x0a, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x53, 0x74, 0x61, 0x74, 0x73,
+	0x12, 0x16, 0x0a, 0x06, 0x6c, 0x6f, 0x61, 0x64, 0x65, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x06, 0x6c, 0x6f, 0x61, 0x64, 0x65, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x72, 0x6f, 0x61, 0x73,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x04, 0x72, 0x6f, 0x61, 0x73, 0x12, 0x14, 0x0a, 0x05,
+	0x6e, 0x6f, 0x64, 0x65, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x6e, 0x6f, 0x64,
+	0x65, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x70, 0x61, 0x6e, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x05, 0x73, 0x70, 0x61, 0x6e, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x72, 0x75, 0x6e, 0x73,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x04, 0x72, 0x75, 0x6e, 0x73, 0x12, 0x14, 0x0a, 0x05,
+	0x62, 0x79, 0x74, 0x65, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x62, 0x79, 0x74,
+	0x65, 0x73, 0x12, 0x1c, 0x0a, 0x09, 0x68, 0x65, 0x61, 0x70, 0x62, 0x79, 0x74, 0x65, 0x73, 0x18,
+	0x07, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x68, 0x65, 0x61, 0x70, 0x62, 0x79, 0x74, 0x65, 0x73,
+	0x12, 0x24, 0x0a, 0x0d, 0x52, 0x46, 0x43, 0x33, 0x33, 0x33, 0x39, 0x6c, 0x6f, 0x61, 0x64, 0x65,
+	0x64, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x52, 0x46, 0x43, 0x33, 0x33, 0x33, 0x39,
+	0x6c, 0x6f, 0x61, 0x64, 0x65, 0x64, 0x12, 0x26, 0x0a, 0x0e, 0x52, 0x46, 0x43, 0x33, 0x33, 0x33,
+	0x39, 0x6c, 0x61, 0x73, 0x74, 0x72, 0x75, 0x6e, 0x18, 0x09, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e,
+	0x52, 0x46, 0x43, 0x33, 0x33, 0x33, 0x39, 0x6c, 0x61, 0x73, 0x74, 0x72, 0x75, 0x6e, 0x22, 0x5b,
+	0x0a, 0x07, 0x54, 0x41, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x75, 0x6e, 0x69,
+	0x78, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x75, 0x6e, 0x69,
+	0x78, 0x74, 0x69, 0x6d, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x52, 0x46, 0x43, 0x33, 0x33, 0x33, 0x39,
+	0x74, 0x69, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x52, 0x46, 0x43, 0x33,
+	0x33, 0x33, 0x39, 0x74, 0x69, 0x6d, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x72, 0x6f, 0x61, 0x73, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x04, 0x72, 0x6f, 0x61, 0x73, 0x22, 0x9c, 0x02, 0x0a, 0x0b,
+	0x54, 0x72, 0x75, 0x73, 0x74, 0x41, 0x6e, 0x63, 0x68, 0x6f, 0x72, 0x12, 0x0e, 0x0a, 0x02, 0x74,
+	0x61, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x74, 0x61, 0x12, 0x1c, 0x0a, 0x09, 0x66,
+	0x69, 0x72, 0x73, 0x74, 0x73, 0x65, 0x65, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09,
+	0x66, 0x69, 0x72, 0x73, 0x74, 0x73, 0x65, 0x65, 0x6e, 0x12, 0x2a, 0x0a, 0x10, 0x52, 0x46, 0x43,
+	0x33, 0x33, 0x33, 0x39, 0x66, 0x69, 0x72, 0x73, 0x74, 0x73, 0x65, 0x65, 0x6e, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x10, 0x52, 0x46, 0x43, 0x33, 0x33, 0x33, 0x39, 0x66, 0x69, 0x72, 0x73,
+	0x74, 0x73, 0x65, 0x65, 0x6e, 0x12, 0x1a, 0x0a, 0x08, 0x6c, 0x61, 0x73, 0x74, 0x73, 0x65, 0x65,
+	0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x6c, 0x61, 0x73, 0x74, 0x73, 0x65, 0x65,
+	0x6e, 0x12, 0x28, 0x0a, 0x0f, 0x52, 0x46, 0x43, 0x33, 0x33, 0x33, 0x39, 0x6c, 0x61, 0x73, 0x74,
+	0x73, 0x65, 0x65, 0x6e, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x52, 0x46, 0x43, 0x33,
+	0x33, 0x33, 0x39, 0x6c, 0x61, 0x73, 0x74, 0x73, 0x65, 0x65, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x72,
+	0x6f, 0x61, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x03, 0x52, 0x04, 0x72, 0x6f, 0x61, 0x73, 0x12,
+	0x2a, 0x0a, 0x06, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x73, 0x18, 0x07, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x12, 0x2e, 0x72, 0x61, 0x72, 0x63, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x54, 0x41, 0x43, 0x6f,
+	0x75, 0x6e, 0x74, 0x52, 0x06, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x73, 0x12, 0x2d, 0x0a, 0x07, 0x6f,
+	0x75, 0x74, 0x61, 0x67, 0x65, 0x73, 0x18, 0x08, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x72,
+	0x61, 0x72, 0x63, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x54, 0x41, 0x4f, 0x75, 0x74, 0x61, 0x67,
+	0x65, 0x52, 0x07, 0x6f, 0x75, 0x74, 0x61, 0x67, 0x65, 0x73, 0x22, 0xac, 0x01, 0x0a, 0x08, 0x54,
+	0x41, 0x4f, 0x75, 0x74, 0x61, 0x67, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x72, 0x74,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x73, 0x74, 0x61, 0x72, 0x74, 0x12, 0x22, 0x0a,
+	0x0c, 0x52, 0x46, 0x43, 0x33, 0x33, 0x33, 0x39, 0x73, 0x74, 0x61, 0x72, 0x74, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0c, 0x52, 0x46, 0x43, 0x33, 0x33, 0x33, 0x39, 0x73, 0x74, 0x61, 0x72,
+	0x74, 0x12, 0x10, 0x0a, 0x03, 0x65, 0x6e, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x03,
+	0x65, 0x6e, 0x64, 0x12, 0x1e, 0x0a, 0x0a, 0x52, 0x46, 0x43, 0x33, 0x33, 0x33, 0x39, 0x65, 0x6e,
+	0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x52, 0x46, 0x43, 0x33, 0x33, 0x33, 0x39,
+	0x65, 0x6e, 0x64, 0x12, 0x1a, 0x0a, 0x08, 0x62, 0x61, 0x73, 0x65, 0x6c, 0x69, 0x6e, 0x65, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x62, 0x61, 0x73, 0x65, 0x6c, 0x69, 0x6e, 0x65, 0x12,
+	0x18, 0x0a, 0x07, 0x62, 0x72, 0x69, 0x64, 0x67, 0x65, 0x64, 0x18, 0x06, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x07, 0x62, 0x72, 0x69, 0x64, 0x67, 0x65, 0x64, 0x22, 0x38, 0x0a, 0x0c, 0x54, 0x72, 0x75,
+	0x73, 0x74, 0x41, 0x6e, 0x63, 0x68, 0x6f, 0x72, 0x73, 0x12, 0x28, 0x0a, 0x03, 0x74, 0x61, 0x73,
+	0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x72, 0x61, 0x72, 0x63, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x2e, 0x54, 0x72, 0x75, 0x73, 0x74, 0x41, 0x6e, 0x63, 0x68, 0x6f, 0x72, 0x52, 0x03,
+	0x74, 0x61, 0x73, 0x22, 0xc0, 0x01, 0x0a, 0x04, 0x41, 0x53, 0x50, 0x41, 0x12, 0x1a, 0x0a, 0x08,
+	0x63, 0x75, 0x73, 0x74, 0x6f, 0x6d, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08,
+	0x63, 0x75, 0x73, 0x74, 0x6f, 0x6d, 0x65, 0x72, 0x12, 0x24, 0x0a, 0x0d, 0x63, 0x75, 0x73, 0x74,
+	0x6f, 0x6d, 0x65, 0x72, 0x61, 0x73, 0x6e, 0x75, 0x6d, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52,
+	0x0d, 0x63, 0x75, 0x73, 0x74, 0x6f, 0x6d, 0x65, 0x72, 0x61, 0x73, 0x6e, 0x75, 0x6d, 0x12, 0x1c,
+	0x0a, 0x09, 0x70, 0x72, 0x6f, 0x76, 0x69, 0x64, 0x65, 0x72, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28,
+	0x09, 0x52, 0x09, 0x70, 0x72, 0x6f, 0x76, 0x69, 0x64, 0x65, 0x72, 0x73, 0x12, 0x0e, 0x0a, 0x02,
+	0x74, 0x61, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x74, 0x61, 0x12, 0x20, 0x0a, 0x0b,
+	0x75, 0x6e, 0x69, 0x78, 0x74, 0x69, 0x6d, 0x65, 0x61, 0x72, 0x72, 0x18, 0x05, 0x20, 0x03, 0x28,
+	0x03, 0x52, 0x0b, 0x75, 0x6e, 0x69, 0x78, 0x74, 0x69, 0x6d, 0x65, 0x61, 0x72, 0x72, 0x12, 0x26,
+	0x0a, 0x0e, 0x52, 0x46, 0x43, 0x33, 0x33, 0x33, 0x39, 0x74, 0x69, 0x6d, 0x65, 0x61, 0x72, 0x72,
+	0x18, 0x06, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0e, 0x52, 0x46, 0x43, 0x33, 0x33, 0x33, 0x39, 0x74,
+	0x69, 0x6d, 0x65, 0x61, 0x72, 0x72, 0x22, 0x30, 0x0a, 0x07, 0x41, 0x53, 0x50, 0x41, 0x41, 0x72,
+	0x72, 0x12, 0x25, 0x0a, 0x05, 0x61, 0x73, 0x70, 0x61, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x0f, 0x2e, 0x72, 0x61, 0x72, 0x63, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x41, 0x53, 0x50,
+	0x41, 0x52, 0x05, 0x61, 0x73, 0x70, 0x61, 0x73, 0x22, 0xb7, 0x01, 0x0a, 0x09, 0x52, 0x6f, 0x75,
+	0x74, 0x65, 0x72, 0x4b, 0x65, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x41, 0x53, 0x4e, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x03, 0x41, 0x53, 0x4e, 0x12, 0x14, 0x0a, 0x05, 0x61, 0x73, 0x6e, 0x75,
+	0x6d, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x05, 0x61, 0x73, 0x6e, 0x75, 0x6d, 0x12, 0x10,
+	0x0a, 0x03, 0x53, 0x4b, 0x49, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x53, 0x4b, 0x49,
+	0x12, 0x16, 0x0a, 0x06, 0x70, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x06, 0x70, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x12, 0x0e, 0x0a, 0x02, 0x74, 0x61, 0x18, 0x05,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x74, 0x61, 0x12, 0x20, 0x0a, 0x0b, 0x75, 0x6e, 0x69, 0x78,
+	0x74, 0x69, 0x6d, 0x65, 0x61, 0x72, 0x72, 0x18, 0x06, 0x20, 0x03, 0x28, 0x03, 0x52, 0x0b, 0x75,
+	0x6e, 0x69, 0x78, 0x74, 0x69, 0x6d, 0x65, 0x61, 0x72, 0x72, 0x12, 0x26, 0x0a, 0x0e, 0x52, 0x46,
+	0x43, 0x33, 0x33, 0x33, 0x39, 0x74, 0x69, 0x6d, 0x65, 0x61, 0x72, 0x72, 0x18, 0x07, 0x20, 0x03,
+	0x28, 0x09, 0x52, 0x0e, 0x52, 0x46, 0x43, 0x33, 0x33, 0x33, 0x39, 0x74, 0x69, 0x6d, 0x65, 0x61,
+	0x72, 0x72, 0x22, 0x38, 0x0a, 0x0c, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x4b, 0x65, 0x79, 0x41,
+	0x72, 0x72, 0x12, 0x28, 0x0a, 0x04, 0x6b, 0x65, 0x79, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x14, 0x2e, 0x72, 0x61, 0x72, 0x63, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x52, 0x6f, 0x75,
+	0x74, 0x65, 0x72, 0x4b, 0x65, 0x79, 0x52, 0x04, 0x6b, 0x65, 0x79, 0x73, 0x22, 0xc8, 0x03, 0x0a,
+	0x0c, 0x53, 0x6c, 0x75, 0x72, 0x6d, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x0e, 0x0a,
+	0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x12, 0x0a,
+	0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d,
+	0x65, 0x12, 0x1a, 0x0a, 0x08, 0x75, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x65, 0x64, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x08, 0x75, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x65, 0x64, 0x12, 0x28, 0x0a,
+	0x0f, 0x52, 0x46, 0x43, 0x33, 0x33, 0x33, 0x39, 0x75, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x65, 0x64,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x52, 0x46, 0x43, 0x33, 0x33, 0x33, 0x39, 0x75,
+	0x70, 0x6c, 0x6f, 0x61, 0x64, 0x65, 0x64, 0x12, 0x24, 0x0a, 0x0d, 0x65, 0x66, 0x66, 0x65, 0x63,
+	0x74, 0x69, 0x76, 0x65, 0x66, 0x72, 0x6f, 0x6d, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0d,
+	0x65, 0x66, 0x66, 0x65, 0x63, 0x74, 0x69, 0x76, 0x65, 0x66, 0x72, 0x6f, 0x6d, 0x12, 0x32, 0x0a,
+	0x14, 0x52, 0x46, 0x43, 0x33, 0x33, 0x33, 0x39, 0x65, 0x66, 0x66, 0x65, 0x63, 0x74, 0x69, 0x76,
+	0x65, 0x66, 0x72, 0x6f, 0x6d, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x14, 0x52, 0x46, 0x43,
+	0x33, 0x33, 0x33, 0x39, 0x65, 0x66, 0x66, 0x65, 0x63, 0x74, 0x69, 0x76, 0x65, 0x66, 0x72, 0x6f,
+	0x6d, 0x12, 0x20, 0x0a, 0x0b, 0x65, 0x66, 0x66, 0x65, 0x63, 0x74, 0x69, 0x76, 0x65, 0x74, 0x6f,
+	0x18, 0x07, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0b, 0x65, 0x66, 0x66, 0x65, 0x63, 0x74, 0x69, 0x76,
+	0x65, 0x74, 0x6f, 0x12, 0x2e, 0x0a, 0x12, 0x52, 0x46, 0x43, 0x33, 0x33, 0x33, 0x39, 0x65, 0x66,
+	0x66, 0x65, 0x63, 0x74, 0x69, 0x76, 0x65, 0x74, 0x6f, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x12, 0x52, 0x46, 0x43, 0x33, 0x33, 0x33, 0x39, 0x65, 0x66, 0x66, 0x65, 0x63, 0x74, 0x69, 0x76,
+	0x65, 0x74, 0x6f, 0x12, 0x24, 0x0a, 0x0d, 0x70, 0x72, 0x65, 0x66, 0x69, 0x78, 0x66, 0x69, 0x6c,
+	0x74, 0x65, 0x72, 0x73, 0x18, 0x09, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0d, 0x70, 0x72, 0x65, 0x66,
+	0x69, 0x78, 0x66, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x73, 0x12, 0x24, 0x0a, 0x0d, 0x62, 0x67, 0x70,
+	0x73, 0x65, 0x63, 0x66, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x73, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x0d, 0x62, 0x67, 0x70, 0x73, 0x65, 0x63, 0x66, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x73, 0x12,
+	0x2a, 0x0a, 0x10, 0x70, 0x72, 0x65, 0x66, 0x69, 0x78, 0x61, 0x73, 0x73, 0x65, 0x72, 0x74, 0x69,
+	0x6f, 0x6e, 0x73, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x05, 0x52, 0x10, 0x70, 0x72, 0x65, 0x66, 0x69,
+	0x78, 0x61, 0x73, 0x73, 0x65, 0x72, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x2a, 0x0a, 0x10, 0x62,
+	0x67, 0x70, 0x73, 0x65, 0x63, 0x61, 0x73, 0x73, 0x65, 0x72, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18,
+	0x0c, 0x20, 0x01, 0x28, 0x05, 0x52, 0x10, 0x62, 0x67, 0x70, 0x73, 0x65, 0x63, 0x61, 0x73, 0x73,
+	0x65, 0x72, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x22, 0x44, 0x0a, 0x0d, 0x53, 0x6c, 0x75, 0x72, 0x6d,
+	0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x33, 0x0a, 0x08, 0x76, 0x65, 0x72, 0x73,
+	0x69, 0x6f, 0x6e, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x72, 0x61, 0x72,
+	0x63, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x53, 0x6c, 0x75, 0x72, 0x6d, 0x56, 0x65, 0x72, 0x73,
+	0x69, 0x6f, 0x6e, 0x52, 0x08, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x22, 0xb8, 0x01,
+	0x0a, 0x08, 0x4c, 0x6f, 0x6f, 0x73, 0x65, 0x52, 0x4f, 0x41, 0x12, 0x2a, 0x0a, 0x03, 0x72, 0x6f,
+	0x61, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x72, 0x61, 0x72, 0x63, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x2e, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x46, 0x72, 0x6f, 0x6d, 0x44,
+	0x42, 0x52, 0x03, 0x72, 0x6f, 0x61, 0x12, 0x1c, 0x0a, 0x09, 0x70, 0x65, 0x72, 0x6d, 0x69, 0x74,
+	0x74, 0x65, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x09, 0x70, 0x65, 0x72, 0x6d, 0x69,
+	0x74, 0x74, 0x65, 0x64, 0x12, 0x32, 0x0a, 0x07, 0x63, 0x6f, 0x76, 0x65, 0x72, 0x65, 0x64, 0x18,
+	0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x72, 0x61, 0x72, 0x63, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x2e, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x46, 0x72, 0x6f, 0x6d, 0x44, 0x42, 0x52,
+	0x07, 0x63, 0x6f, 0x76, 0x65, 0x72, 0x65, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x74,
+	0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x12, 0x18,
+	0x0a, 0x07, 0x6d, 0x69, 0x6e, 0x69, 0x6d, 0x61, 0x6c, 0x18, 0x05, 0x20, 0x03, 0x28, 0x09, 0x52,
+	0x07, 0x6d, 0x69, 0x6e, 0x69, 0x6d, 0x61, 0x6c, 0x22, 0x86, 0x01, 0x0a, 0x0a, 0x4c, 0x6f, 0x6f,
+	0x73, 0x65, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x1a, 0x0a, 0x08, 0x75, 0x6e, 0x69,
+	0x78, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x75, 0x6e, 0x69,
+	0x78, 0x74, 0x69, 0x6d, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x52, 0x46, 0x43, 0x33, 0x33, 0x33, 0x39,
+	0x74, 0x69, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x52, 0x46, 0x43, 0x33,
+	0x33, 0x33, 0x39, 0x74, 0x69, 0x6d, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x72, 0x6f, 0x61, 0x73, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x04, 0x72, 0x6f, 0x61, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x6c,
+	0x6f, 0x6f, 0x73, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x6c, 0x6f, 0x6f, 0x73,
+	0x65, 0x22, 0x4f, 0x0a, 0x0c, 0x4c, 0x6f, 0x6f, 0x73, 0x65, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x72,
+	0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03,
+	0x6b, 0x65, 0x79, 0x12, 0x2d, 0x0a, 0x06, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x73, 0x18, 0x02, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x72, 0x61, 0x72, 0x63, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e,
+	0x4c, 0x6f, 0x6f, 0x73, 0x65, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x52, 0x06, 0x63, 0x6f, 0x75, 0x6e,
+	0x74, 0x73, 0x22, 0xb0, 0x02, 0x0a, 0x0c, 0x4d, 0x61, 0x78, 0x4c, 0x65, 0x6e, 0x52, 0x65, 0x70,
+	0x6f, 0x72, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x75, 0x6e, 0x69, 0x78, 0x74, 0x69, 0x6d, 0x65, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x75, 0x6e, 0x69, 0x78, 0x74, 0x69, 0x6d, 0x65, 0x12,
+	0x20, 0x0a, 0x0b, 0x52, 0x46, 0x43, 0x33, 0x33, 0x33, 0x39, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x52, 0x46, 0x43, 0x33, 0x33, 0x33, 0x39, 0x74, 0x69, 0x6d,
+	0x65, 0x12, 0x12, 0x0a, 0x04, 0x72, 0x6f, 0x61, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x04, 0x72, 0x6f, 0x61, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x6f, 0x6f, 0x73, 0x65, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x6c, 0x6f, 0x6f, 0x73, 0x65, 0x12, 0x27, 0x0a, 0x03, 0x74,
+	0x61, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x72, 0x61, 0x72, 0x63, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4c, 0x6f, 0x6f, 0x73, 0x65, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x52,
+	0x03, 0x74, 0x61, 0x73, 0x12, 0x29, 0x0a, 0x04, 0x61, 0x73, 0x6e, 0x73, 0x18, 0x06, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x15, 0x2e, 0x72, 0x61, 0x72, 0x63, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4c,
+	0x6f, 0x6f, 0x73, 0x65, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x52, 0x04, 0x61, 0x73, 0x6e, 0x73, 0x12,
+	0x31, 0x0a, 0x09, 0x6c, 0x6f, 0x6f, 0x73, 0x65, 0x72, 0x6f, 0x61, 0x73, 0x18, 0x07, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x13, 0x2e, 0x72, 0x61, 0x72, 0x63, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4c,
+	0x6f, 0x6f, 0x73, 0x65, 0x52, 0x4f, 0x41, 0x52, 0x09, 0x6c, 0x6f, 0x6f, 0x73, 0x65, 0x72, 0x6f,
+	0x61, 0x73, 0x12, 0x31, 0x0a, 0x07, 0x68, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x18, 0x08, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x72, 0x61, 0x72, 0x63, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e,
+	0x4c, 0x6f, 0x6f, 0x73, 0x65, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x52, 0x07, 0x68, 0x69,
+	0x73, 0x74, 0x6f, 0x72, 0x79, 0x22, 0x93, 0x02, 0x0a, 0x05, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12,
+	0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12,
+	0x1a, 0x0a, 0x08, 0x75, 0x6e, 0x69, 0x78, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x08, 0x75, 0x6e, 0x69, 0x78, 0x74, 0x69, 0x6d, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x52,
+	0x46, 0x43, 0x33, 0x33, 0x33, 0x39, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0b, 0x52, 0x46, 0x43, 0x33, 0x33, 0x33, 0x39, 0x74, 0x69, 0x6d, 0x65, 0x12, 0x12, 0x0a,
+	0x04, 0x6b, 0x69, 0x6e, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6b, 0x69, 0x6e,
+	0x64, 0x12, 0x1a, 0x0a, 0x08, 0x73, 0x65, 0x76, 0x65, 0x72, 0x69, 0x74, 0x79, 0x18, 0x05, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x08, 0x73, 0x65, 0x76, 0x65, 0x72, 0x69, 0x74, 0x79, 0x12, 0x16, 0x0a,
+	0x06, 0x70, 0x72, 0x65, 0x66, 0x69, 0x78, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x70,
+	0x72, 0x65, 0x66, 0x69, 0x78, 0x12, 0x10, 0x0a, 0x03, 0x41, 0x53, 0x4e, 0x18, 0x07, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x03, 0x41, 0x53, 0x4e, 0x12, 0x14, 0x0a, 0x05, 0x61, 0x73, 0x6e, 0x75, 0x6d,
+	0x18, 0x08, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x05, 0x61, 0x73, 0x6e, 0x75, 0x6d, 0x12, 0x22, 0x0a,
+	0x0c, 0x70, 0x72, 0x65, 0x76, 0x69, 0x6f, 0x75, 0x73, 0x41, 0x53, 0x4e, 0x73, 0x18, 0x09, 0x20,
+	0x03, 0x28, 0x09, 0x52, 0x0c, 0x70, 0x72, 0x65, 0x76, 0x69, 0x6f, 0x75, 0x73, 0x41, 0x53, 0x4e,
+	0x73, 0x12, 0x10, 0x0a, 0x03, 0x74, 0x61, 0x73, 0x18, 0x0a, 0x20, 0x03, 0x28, 0x09, 0x52, 0x03,
+	0x74, 0x61, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x64, 0x65, 0x74, 0x61, 0x69, 0x6c, 0x18, 0x0b, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x06, 0x64, 0x65, 0x74, 0x61, 0x69, 0x6c, 0x22, 0x32, 0x0a, 0x06, 0x45,
+	0x76, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x28, 0x0a, 0x06, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x18,
+	0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x72, 0x61, 0x72, 0x63, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x2e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x52, 0x06, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x22,
+	0xdd, 0x01, 0x0a, 0x09, 0x57, 0x61, 0x74, 0x63, 0x68, 0x6c, 0x69, 0x73, 0x74, 0x12, 0x0e, 0x0a,
+	0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x12, 0x0a,
+	0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d,
+	0x65, 0x12, 0x12, 0x0a, 0x04, 0x61, 0x73, 0x6e, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52,
+	0x04, 0x61, 0x73, 0x6e, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x72, 0x65, 0x66, 0x69, 0x78, 0x65,
+	0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x09, 0x52, 0x08, 0x70, 0x72, 0x65, 0x66, 0x69, 0x78, 0x65,
+	0x73, 0x12, 0x10, 0x0a, 0x03, 0x74, 0x61, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x09, 0x52, 0x03,
+	0x74, 0x61, 0x73, 0x12, 0x10, 0x0a, 0x03, 0x75, 0x72, 0x6c, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x03, 0x75, 0x72, 0x6c, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x65, 0x63, 0x72, 0x65, 0x74, 0x18,
+	0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x65, 0x63, 0x72, 0x65, 0x74, 0x12, 0x18, 0x0a,
+	0x07, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x18, 0x08, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07,
+	0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x12, 0x26, 0x0a, 0x0e, 0x52, 0x46, 0x43, 0x33, 0x33,
+	0x33, 0x39, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x18, 0x09, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0e, 0x52, 0x46, 0x43, 0x33, 0x33, 0x33, 0x39, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x22,
+	0x42, 0x0a, 0x0a, 0x57, 0x61, 0x74, 0x63, 0x68, 0x6c, 0x69, 0x73, 0x74, 0x73, 0x12, 0x34, 0x0a,
+	0x0a, 0x77, 0x61, 0x74, 0x63, 0x68, 0x6c, 0x69, 0x73, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x14, 0x2e, 0x72, 0x61, 0x72, 0x63, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x57, 0x61,
+	0x74, 0x63, 0x68, 0x6c, 0x69, 0x73, 0x74, 0x52, 0x0a, 0x77, 0x61, 0x74, 0x63, 0x68, 0x6c, 0x69,
+	0x73, 0x74, 0x73, 0x22, 0xf5, 0x02, 0x0a, 0x11, 0x57, 0x61, 0x74, 0x63, 0x68, 0x4e, 0x6f, 0x74,
+	0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1c, 0x0a, 0x09, 0x77, 0x61, 0x74,
+	0x63, 0x68, 0x6c, 0x69, 0x73, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x77, 0x61,
+	0x74, 0x63, 0x68, 0x6c, 0x69, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x72,
+	0x75, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x72, 0x75, 0x6e, 0x12, 0x1a, 0x0a,
+	0x08, 0x75, 0x6e, 0x69, 0x78, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x08, 0x75, 0x6e, 0x69, 0x78, 0x74, 0x69, 0x6d, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x52, 0x46, 0x43,
+	0x33, 0x33, 0x33, 0x39, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b,
+	0x52, 0x46, 0x43, 0x33, 0x33, 0x33, 0x39, 0x74, 0x69, 0x6d, 0x65, 0x12, 0x2e, 0x0a, 0x05, 0x61,
+	0x64, 0x64, 0x65, 0x64, 0x18, 0x06, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x72, 0x61, 0x72,
+	0x63, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x46, 0x72,
+	0x6f, 0x6d, 0x44, 0x42, 0x52, 0x05, 0x61, 0x64, 0x64, 0x65, 0x64, 0x12, 0x32, 0x0a, 0x07, 0x72,
+	0x65, 0x6d, 0x6f, 0x76, 0x65, 0x64, 0x18, 0x07, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x72,
+	0x61, 0x72, 0x63, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73,
+	0x46, 0x72, 0x6f, 0x6d, 0x44, 0x42, 0x52, 0x07, 0x72, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x64, 0x12,
+	0x3a, 0x0a, 0x0b, 0x6f, 0x75, 0x74, 0x61, 0x67, 0x65, 0x61, 0x64, 0x64, 0x65, 0x64, 0x18, 0x08,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x72, 0x61, 0x72, 0x63, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x2e, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x46, 0x72, 0x6f, 0x6d, 0x44, 0x42, 0x52, 0x0b,
+	0x6f, 0x75, 0x74, 0x61, 0x67, 0x65, 0x61, 0x64, 0x64, 0x65, 0x64, 0x12, 0x3e, 0x0a, 0x0d, 0x6f,
+	0x75, 0x74, 0x61, 0x67, 0x65, 0x72, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x64, 0x18, 0x09, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x18, 0x2e, 0x72, 0x61, 0x72, 0x63, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x52,
+	0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x46, 0x72, 0x6f, 0x6d, 0x44, 0x42, 0x52, 0x0d, 0x6f, 0x75,
+	0x74, 0x61, 0x67, 0x65, 0x72, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x64, 0x22, 0xb9, 0x02, 0x0a, 0x07,
+	0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x73, 0x12, 0x10, 0x0a, 0x03, 0x72, 0x75, 0x6e, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x72, 0x75, 0x6e, 0x12, 0x1a, 0x0a, 0x08, 0x75, 0x6e, 0x69,
+	0x78, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x75, 0x6e, 0x69,
+	0x78, 0x74, 0x69, 0x6d, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x52, 0x46, 0x43, 0x33, 0x33, 0x33, 0x39,
+	0x74, 0x69, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x52, 0x46, 0x43, 0x33,
+	0x33, 0x33, 0x39, 0x74, 0x69, 0x6d, 0x65, 0x12, 0x2e, 0x0a, 0x05, 0x61, 0x64, 0x64, 0x65, 0x64,
+	0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x72, 0x61, 0x72, 0x63, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x2e, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x46, 0x72, 0x6f, 0x6d, 0x44, 0x42,
+	0x52, 0x05, 0x61, 0x64, 0x64, 0x65, 0x64, 0x12, 0x32, 0x0a, 0x07, 0x72, 0x65, 0x6d, 0x6f, 0x76,
+	0x65, 0x64, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x72, 0x61, 0x72, 0x63, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x46, 0x72, 0x6f, 0x6d,
+	0x44, 0x42, 0x52, 0x07, 0x72, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x64, 0x12, 0x3a, 0x0a, 0x0b, 0x6f,
+	0x75, 0x74, 0x61, 0x67, 0x65, 0x61, 0x64, 0x64, 0x65, 0x64, 0x18, 0x06, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x18, 0x2e, 0x72, 0x61, 0x72, 0x63, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x52, 0x65, 0x73,
+	0x75, 0x6c, 0x74, 0x73, 0x46, 0x72, 0x6f, 0x6d, 0x44, 0x42, 0x52, 0x0b, 0x6f, 0x75, 0x74, 0x61,
+	0x67, 0x65, 0x61, 0x64, 0x64, 0x65, 0x64, 0x12, 0x3e, 0x0a, 0x0d, 0x6f, 0x75, 0x74, 0x61, 0x67,
+	0x65, 0x72, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x64, 0x18, 0x07, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x18,
+	0x2e, 0x72, 0x61, 0x72, 0x63, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x52, 0x65, 0x73, 0x75, 0x6c,
+	0x74, 0x73, 0x46, 0x72, 0x6f, 0x6d, 0x44, 0x42, 0x52, 0x0d, 0x6f, 0x75, 0x74, 0x61, 0x67, 0x65,
+	0x72, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x64, 0x22, 0xae, 0x02, 0x0a, 0x0a, 0x53, 0x75, 0x62, 0x73,
+	0x63, 0x72, 0x69, 0x62, 0x65, 0x72, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x6d,
+	0x61, 0x69, 0x6c, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x6d, 0x61, 0x69, 0x6c,
+	0x12, 0x12, 0x0a, 0x04, 0x61, 0x73, 0x6e, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x09, 0x52, 0x04,
+	0x61, 0x73, 0x6e, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x72, 0x65, 0x66, 0x69, 0x78, 0x65, 0x73,
+	0x18, 0x05, 0x20, 0x03, 0x28, 0x09, 0x52, 0x08, 0x70, 0x72, 0x65, 0x66, 0x69, 0x78, 0x65, 0x73,
+	0x12, 0x10, 0x0a, 0x03, 0x74, 0x61, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x09, 0x52, 0x03, 0x74,
+	0x61, 0x73, 0x12, 0x1c, 0x0a, 0x09, 0x66, 0x72, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x79, 0x18,
+	0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x66, 0x72, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x79,
+	0x12, 0x18, 0x0a, 0x07, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x18, 0x08, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x07, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x12, 0x26, 0x0a, 0x0e, 0x52, 0x46,
+	0x43, 0x33, 0x33, 0x33, 0x39, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x18, 0x09, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0e, 0x52, 0x46, 0x43, 0x33, 0x33, 0x33, 0x39, 0x63, 0x72, 0x65, 0x61, 0x74,
+	0x65, 0x64, 0x12, 0x1a, 0x0a, 0x08, 0x6c, 0x61, 0x73, 0x74, 0x73, 0x65, 0x6e, 0x74, 0x18, 0x0a,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x6c, 0x61, 0x73, 0x74, 0x73, 0x65, 0x6e, 0x74, 0x12, 0x28,
+	0x0a, 0x0f, 0x52, 0x46, 0x43, 0x33, 0x33, 0x33, 0x39, 0x6c, 0x61, 0x73, 0x74, 0x73, 0x65, 0x6e,
+	0x74, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x52, 0x46, 0x43, 0x33, 0x33, 0x33, 0x39,
+	0x6c, 0x61, 0x73, 0x74, 0x73, 0x65, 0x6e, 0x74, 0x22, 0x46, 0x0a, 0x0b, 0x53, 0x75, 0x62, 0x73,
+	0x63, 0x72, 0x69, 0x62, 0x65, 0x72, 0x73, 0x12, 0x37, 0x0a, 0x0b, 0x73, 0x75, 0x62, 0x73, 0x63,
+	0x72, 0x69, 0x62, 0x65, 0x72, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x72,
+	0x61, 0x72, 0x63, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69,
+	0x62, 0x65, 0x72, 0x52, 0x0b, 0x73, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x72, 0x73,
+	0x22, 0x82, 0x02, 0x0a, 0x08, 0x4c, 0x69, 0x66, 0x65, 0x73, 0x70, 0x61, 0x6e, 0x12, 0x1c, 0x0a,
+	0x09, 0x66, 0x69, 0x72, 0x73, 0x74, 0x73, 0x65, 0x65, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x09, 0x66, 0x69, 0x72, 0x73, 0x74, 0x73, 0x65, 0x65, 0x6e, 0x12, 0x2a, 0x0a, 0x10, 0x52,
+	0x46, 0x43, 0x33, 0x33, 0x33, 0x39, 0x66, 0x69, 0x72, 0x73, 0x74, 0x73, 0x65, 0x65, 0x6e, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x10, 0x52, 0x46, 0x43, 0x33, 0x33, 0x33, 0x39, 0x66, 0x69,
+	0x72, 0x73, 0x74, 0x73, 0x65, 0x65, 0x6e, 0x12, 0x1a, 0x0a, 0x08, 0x6c, 0x61, 0x73, 0x74, 0x73,
+	0x65, 0x65, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x6c, 0x61, 0x73, 0x74, 0x73,
+	0x65, 0x65, 0x6e, 0x12, 0x28, 0x0a, 0x0f, 0x52, 0x46, 0x43, 0x33, 0x33, 0x33, 0x39, 0x6c, 0x61,
+	0x73, 0x74, 0x73, 0x65, 0x65, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x52, 0x46,
+	0x43, 0x33, 0x33, 0x33, 0x39, 0x6c, 0x61, 0x73, 0x74, 0x73, 0x65, 0x65, 0x6e, 0x12, 0x18, 0x0a,
+	0x07, 0x70, 0x72, 0x65, 0x73, 0x65, 0x6e, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07,
+	0x70, 0x72, 0x65, 0x73, 0x65, 0x6e, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x67, 0x61, 0x70, 0x73, 0x18,
+	0x06, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x67, 0x61, 0x70, 0x73, 0x12, 0x1e, 0x0a, 0x0a, 0x6c,
+	0x6f, 0x6e, 0x67, 0x65, 0x73, 0x74, 0x67, 0x61, 0x70, 0x18, 0x07, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x0a, 0x6c, 0x6f, 0x6e, 0x67, 0x65, 0x73, 0x74, 0x67, 0x61, 0x70, 0x12, 0x18, 0x0a, 0x07, 0x63,
+	0x75, 0x72, 0x72, 0x65, 0x6e, 0x74, 0x18, 0x08, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x63, 0x75,
+	0x72, 0x72, 0x65, 0x6e, 0x74, 0x22, 0x7a, 0x0a, 0x0a, 0x43, 0x68, 0x75, 0x72, 0x6e, 0x43, 0x6f,
+	0x75, 0x6e, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x75, 0x6e, 0x69, 0x78, 0x74, 0x69, 0x6d, 0x65, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x75, 0x6e, 0x69, 0x78, 0x74, 0x69, 0x6d, 0x65, 0x12,
+	0x20, 0x0a, 0x0b, 0x52, 0x46, 0x43, 0x33, 0x33, 0x33, 0x39, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x52, 0x46, 0x43, 0x33, 0x33, 0x33, 0x39, 0x74, 0x69, 0x6d,
+	0x65, 0x12, 0x14, 0x0a, 0x05, 0x61, 0x64, 0x64, 0x65, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x05, 0x61, 0x64, 0x64, 0x65, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x72, 0x65, 0x6d, 0x6f, 0x76,
+	0x65, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x72, 0x65, 0x6d, 0x6f, 0x76, 0x65,
+	0x64, 0x22, 0x78, 0x0a, 0x0b, 0x43, 0x68, 0x75, 0x72, 0x6e, 0x53, 0x65, 0x72, 0x69, 0x65, 0x73,
+	0x12, 0x0e, 0x0a, 0x02, 0x74, 0x61, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x74, 0x61,
+	0x12, 0x14, 0x0a, 0x05, 0x61, 0x64, 0x64, 0x65, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x05, 0x61, 0x64, 0x64, 0x65, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x72, 0x65, 0x6d, 0x6f, 0x76, 0x65,
+	0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x72, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x64,
+	0x12, 0x29, 0x0a, 0x04, 0x64, 0x61, 0x79, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x15,
+	0x2e, 0x72, 0x61, 0x72, 0x63, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x43, 0x68, 0x75, 0x72, 0x6e,
+	0x43, 0x6f, 0x75, 0x6e, 0x74, 0x52, 0x04, 0x64, 0x61, 0x79, 0x73, 0x22, 0x31, 0x0a, 0x05, 0x43,
+	0x68, 0x75, 0x72, 0x6e, 0x12, 0x28, 0x0a, 0x03, 0x74, 0x61, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x16, 0x2e, 0x72, 0x61, 0x72, 0x63, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x43, 0x68,
+	0x75, 0x72, 0x6e, 0x53, 0x65, 0x72, 0x69, 0x65, 0x73, 0x52, 0x03, 0x74, 0x61, 0x73, 0x42, 0x09,
+	0x5a, 0x07, 0x2e, 0x3b, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x33,
+}
+
+var (
+	file_rarc_proto_rawDescOnce sync.Once
+	file_rarc_proto_rawDescData = file_rarc_proto_rawDesc
+)
+
+func file_rarc_proto_rawDescGZIP() []byte {
+	file_rarc_proto_rawDescOnce.Do(func() {
+		file_rarc_proto_rawDescData = protoimpl.X.CompressGZIP(file_rarc_proto_rawDescData)
+	})
+	return file_rarc_proto_rawDescData
+}
+
+var file_rarc_proto_msgTypes = make([]protoimpl.MessageInfo, 32)
+var file_rarc_proto_goTypes = []interface{}{
+	(*ResultsFromDB)(nil),        // 0: rarcproto.ResultsFromDB
+	(*Provenance)(nil),           // 1: rarcproto.Provenance
+	(*ResultsFromDBRFC3339)(nil), // 2: rarcproto.ResultsFromDBRFC3339
+	(*ResultArr)(nil),            // 3: rarcproto.ResultArr
+	(*Validity)(nil),             // 4: rarcproto.Validity
+	(*IndexStats)(nil),           // 5: rarcproto.IndexStats
+	(*TACount)(nil),              // 6: rarcproto.TACount
+	(*TrustAnchor)(nil),          // 7: rarcproto.TrustAnchor
+	(*TAOutage)(nil),             // 8: rarcproto.TAOutage
+	(*TrustAnchors)(nil),         // 9: rarcproto.TrustAnchors
+	(*ASPA)(nil),                 // 10: rarcproto.ASPA
+	(*ASPAArr)(nil),              // 11: rarcproto.ASPAArr
+	(*RouterKey)(nil),            // 12: rarcproto.RouterKey
+	(*RouterKeyArr)(nil),         // 13: rarcproto.RouterKeyArr
+	(*SlurmVersion)(nil),         // 14: rarcproto.SlurmVersion
+	(*SlurmVersions)(nil),        // 15: rarcproto.SlurmVersions
+	(*LooseROA)(nil),             // 16: rarcproto.LooseROA
+	(*LooseCount)(nil),           // 17: rarcproto.LooseCount
+	(*LooseHistory)(nil),         // 18: rarcproto.LooseHistory
+	(*MaxLenReport)(nil),         // 19: rarcproto.MaxLenReport
+	(*Event)(nil),                // 20: rarcproto.Event
+	(*Events)(nil),               // 21: rarcproto.Events
+	(*Watchlist)(nil),            // 22: rarcproto.Watchlist
+	(*Watchlists)(nil),           // 23: rarcproto.Watchlists
+	(*WatchNotification)(nil),    // 24: rarcproto.WatchNotification
+	(*Changes)(nil),              // 25: rarcproto.Changes
+	(*Subscriber)(nil),           // 26: rarcproto.Subscriber
+	(*Subscribers)(nil),          // 27: rarcproto.Subscribers
+	(*Lifespan)(nil),             // 28: rarcproto.Lifespan
+	(*ChurnCount)(nil),           // 29: rarcproto.ChurnCount
+	(*ChurnSeries)(nil),          // 30: rarcproto.ChurnSeries
+	(*Churn)(nil),                // 31: rarcproto.Churn
+}
+var file_rarc_proto_depIdxs = []int32{
+	1,  // 0: rarcproto.ResultsFromDB.provenance:type_name -> rarcproto.Provenance
+	28, // 1: rarcproto.ResultsFromDB.lifespan:type_name -> rarcproto.Lifespan
+	0,  // 2: rarcproto.ResultArr.results:type_name -> rarcproto.ResultsFromDB
+	0,  // 3: rarcproto.Validity.matched:type_name -> rarcproto.ResultsFromDB
+	0,  // 4: rarcproto.Validity.unmatched:type_name -> rarcproto.ResultsFromDB
+	6,  // 5: rarcproto.TrustAnchor.counts:type_name -> rarcproto.TACount
+	8,  // 6: rarcproto.TrustAnchor.outages:type_name -> rarcproto.TAOutage
+	7,  // 7: rarcproto.TrustAnchors.tas:type_name -> rarcproto.TrustAnchor
+	10, // 8: rarcproto.ASPAArr.aspas:type_name -> rarcproto.ASPA
+	12, // 9: rarcproto.RouterKeyArr.keys:type_name -> rarcproto.RouterKey
+	14, // 10: rarcproto.SlurmVersions.versions:type_name -> rarcproto.SlurmVersion
+	0,  // 11: rarcproto.LooseROA.roa:type_name -> rarcproto.ResultsFromDB
+	0,  // 12: rarcproto.LooseROA.covered:type_name -> rarcproto.ResultsFromDB
+	17, // 13: rarcproto.LooseHistory.counts:type_name -> rarcproto.LooseCount
+	17, // 14: rarcproto.MaxLenReport.tas:type_name -> rarcproto.LooseCount
+	17, // 15: rarcproto.MaxLenReport.asns:type_name -> rarcproto.LooseCount
+	16, // 16: rarcproto.MaxLenReport.looseroas:type_name -> rarcproto.LooseROA
+	18, // 17: rarcproto.MaxLenReport.history:type_name -> rarcproto.LooseHistory
+	20, // 18: rarcproto.Events.events:type_name -> rarcproto.Event
+	22, // 19: rarcproto.Watchlists.watchlists:type_name -> rarcproto.Watchlist
+	0,  // 20: rarcproto.WatchNotification.added:type_name -> rarcproto.ResultsFromDB
+	0,  // 21: rarcproto.WatchNotification.removed:type_name -> rarcproto.ResultsFromDB
+	0,  // 22: rarcproto.WatchNotification.outageadded:type_name -> rarcproto.ResultsFromDB
+	0,  // 23: rarcproto.WatchNotification.outageremoved:type_name -> rarcproto.ResultsFromDB
+	0,  // 24: rarcproto.Changes.added:type_name -> rarcproto.ResultsFromDB
+	0,  // 25: rarcproto.Changes.removed:type_name -> rarcproto.ResultsFromDB
+	0,  // 26: rarcproto.Changes.outageadded:type_name -> rarcproto.ResultsFromDB
+	0,  // 27: rarcproto.Changes.outageremoved:type_name -> rarcproto.ResultsFromDB
+	26, // 28: rarcproto.Subscribers.subscribers:type_name -> rarcproto.Subscriber
+	29, // 29: rarcproto.ChurnSeries.days:type_name -> rarcproto.ChurnCount
+	30, // 30: rarcproto.Churn.tas:type_name -> rarcproto.ChurnSeries
+	31, // [31:31] is the sub-list for method output_type
+	31, // [31:31] is the sub-list for method input_type
+	31, // [31:31] is the sub-list for extension type_name
+	31, // [31:31] is the sub-list for extension extendee
+	0,  // [0:31] is the sub-list for field type_name
+}
+
+func init() { file_rarc_proto_init() }
+func file_rarc_proto_init() {
+	if File_rarc_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_rarc_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ResultsFromDB); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_rarc_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Provenance); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_rarc_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ResultsFromDBRFC3339); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_rarc_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ResultArr); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_rarc_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Validity); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_rarc_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*IndexStats); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_rarc_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TACount); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_rarc_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TrustAnchor); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_rarc_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TAOutage); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_rarc_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TrustAnchors); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_rarc_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ASPA); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_rarc_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ASPAArr); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_rarc_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RouterKey); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_rarc_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RouterKeyArr); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_rarc_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SlurmVersion); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_rarc_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SlurmVersions); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_rarc_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LooseROA); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_rarc_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LooseCount); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_rarc_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LooseHistory); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_rarc_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MaxLenReport); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_rarc_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Event); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_rarc_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Events); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_rarc_proto_msgTypes[22].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Watchlist); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_rarc_proto_msgTypes[23].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Watchlists); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_rarc_proto_msgTypes[24].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*WatchNotification); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_rarc_proto_msgTypes[25].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Changes); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_rarc_proto_msgTypes[26].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Subscriber); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_rarc_proto_msgTypes[27].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Subscribers); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_rarc_proto_msgTypes[28].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Lifespan); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_rarc_proto_msgTypes[29].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ChurnCount); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_rarc_proto_msgTypes[30].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ChurnSeries); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_rarc_proto_msgTypes[31].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Churn); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -404,7 +3667,7 @@ func file_rarc_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_rarc_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   3,
+			NumMessages:   32,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
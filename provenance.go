@@ -0,0 +1,171 @@
+package main
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"cloud.google.com/go/bigquery"
+	pb "github.com/gidoBOSSftw5731/Historical-ROA/proto"
+)
+
+// inputSource is where a VRP came from, for validators that say: the .roa
+// object's URI, its EE certificate's SKI and that certificate's validity
+type inputSource struct {
+	URI       string    `json:"uri,omitempty"`
+	SKI       string    `json:"ski,omitempty"`
+	NotBefore time.Time `json:"notBefore,omitempty"`
+	NotAfter  time.Time `json:"notAfter,omitempty"`
+}
+
+// UnmarshalJSON takes the validity window either flat or in a "validity"
+// object like Routinator's extended JSON. Times that don't parse are left
+// out rather than failing the VRP.
+func (s *inputSource) UnmarshalJSON(b []byte) error {
+	type times struct {
+		NotBefore string `json:"notBefore"`
+		NotAfter  string `json:"notAfter"`
+	}
+	var v struct {
+		URI string `json:"uri"`
+		SKI string `json:"ski"`
+		times
+		Validity times `json:"validity"`
+	}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+
+	*s = inputSource{URI: v.URI, SKI: v.SKI}
+	if v.Validity != (times{}) {
+		v.times = v.Validity
+	}
+	s.NotBefore, _ = time.Parse(time.RFC3339, v.NotBefore)
+	s.NotAfter, _ = time.Parse(time.RFC3339, v.NotAfter)
+	return nil
+}
+
+func (s inputSource) empty() bool {
+	return s == inputSource{}
+}
+
+// normaliseSKI puts an SKI in uppercase hex without colons, validators
+// differ
+func normaliseSKI(s string) (string, error) {
+	b, err := hex.DecodeString(strings.Replace(strings.TrimSpace(s), ":", "", -1))
+	if err != nil || len(b) != 20 {
+		return "", fmt.Errorf("bad SKI %q", s)
+	}
+	return strings.ToUpper(hex.EncodeToString(b)), nil
+}
+
+// withSource is s with the provenance from src. A bad SKI is dropped rather
+// than losing the VRP.
+func (s storedROA) withSource(src inputSource) storedROA {
+	s.URI = strings.TrimSpace(src.URI)
+	s.SKI, _ = normaliseSKI(src.SKI)
+	if !src.NotBefore.IsZero() {
+		s.NotBefore = bigquery.NullTimestamp{Timestamp: src.NotBefore, Valid: true}
+	}
+	if !src.NotAfter.IsZero() {
+		s.NotAfter = bigquery.NullTimestamp{Timestamp: src.NotAfter, Valid: true}
+	}
+	return s
+}
+
+// provenanceRow is a row of provenance_arr: one source for a ROA in
+// roas_arr, and every update it was seen from there
+type provenanceRow struct {
+	Asn         string                 `bigquery:"asn"`
+	Prefix      string                 `bigquery:"prefix"`
+	Mask        int                    `bigquery:"mask"`
+	Maxlen      int                    `bigquery:"maxlen"`
+	Ta          string                 `bigquery:"ta"`
+	URI         string                 `bigquery:"uri"`
+	SKI         string                 `bigquery:"ski"`
+	NotBefore   bigquery.NullTimestamp `bigquery:"notbefore"`
+	NotAfter    bigquery.NullTimestamp `bigquery:"notafter"`
+	InsertTimes []time.Time            `bigquery:"inserttimes"`
+}
+
+// mergeProvenance adds the sources of the staged ROAs to provenance_arr
+func mergeProvenance(ctx context.Context, bufName string, fetched time.Time) error {
+	if _, err := ensureTable(ctx, "provenance_arr", provenanceRow{}); err != nil {
+		return fmt.Errorf("error updating provenance_arr: %v", err)
+	}
+
+	query := client.Query(`MERGE historical.provenance_arr arr
+	USING (SELECT DISTINCT Asn, Prefix, Subnet, MaxLength, Ta, URI, SKI, NotBefore, NotAfter
+		FROM historical.` + bufName + ` WHERE URI != "" OR SKI != "") b
+	ON b.Asn = arr.asn AND b.Prefix = arr.prefix AND b.Subnet = arr.mask
+	AND b.MaxLength = arr.maxlen AND b.Ta = arr.ta
+	AND b.URI = arr.uri AND b.SKI = arr.ski
+	AND IFNULL(b.NotBefore, TIMESTAMP_SECONDS(0)) = IFNULL(arr.notbefore, TIMESTAMP_SECONDS(0))
+	AND IFNULL(b.NotAfter, TIMESTAMP_SECONDS(0)) = IFNULL(arr.notafter, TIMESTAMP_SECONDS(0))
+	WHEN MATCHED THEN
+		UPDATE SET inserttimes = ` + addTimeSQL("arr.inserttimes") + `
+	WHEN NOT MATCHED BY TARGET THEN
+		INSERT (asn, prefix, mask, maxlen, ta, uri, ski, notbefore, notafter, inserttimes)
+		VALUES (b.Asn, b.Prefix, b.Subnet, b.MaxLength, b.Ta, b.URI, b.SKI, b.NotBefore, b.NotAfter,
+			[@fetched])`)
+	query.Parameters = []bigquery.QueryParameter{{Name: "fetched", Value: fetched}}
+	_, err := runQuery(ctx, query)
+	return err
+}
+
+// provenanceTable makes sure provenance_arr exists before the first lookup
+// that joins it, even if nothing has had provenance yet
+var provenanceTable struct {
+	once sync.Once
+	err  error
+}
+
+func ensureProvenance(ctx context.Context) error {
+	provenanceTable.once.Do(func() {
+		_, provenanceTable.err = ensureTable(ctx, "provenance_arr", provenanceRow{})
+	})
+	return provenanceTable.err
+}
+
+// provenanceColumn selects the sources of a roas_arr row r as an array
+const provenanceColumn = `ARRAY(SELECT AS STRUCT p.uri, p.ski, p.notbefore, p.notafter, p.inserttimes
+		FROM historical.provenance_arr p
+		WHERE p.asn = r.asn AND p.prefix = r.prefix AND p.mask = r.mask
+		AND p.maxlen = r.maxlen AND p.ta = r.ta
+		ORDER BY p.inserttimes[SAFE_OFFSET(0)] DESC)`
+
+// provenanceResults converts provenanceColumn from a row
+func provenanceResults(v bigquery.Value) []*pb.Provenance {
+	var out []*pb.Provenance
+	list, _ := v.([]bigquery.Value)
+	for _, item := range list {
+		f, ok := item.([]bigquery.Value)
+		if !ok || len(f) < 5 {
+			continue
+		}
+		p := &pb.Provenance{}
+		p.Uri, _ = f[0].(string)
+		p.SKI, _ = f[1].(string)
+		if t, ok := f[2].(time.Time); ok {
+			p.Notbefore = t.Unix()
+			p.RFC3339Notbefore = t.Format(time.RFC3339)
+		}
+		if t, ok := f[3].(time.Time); ok {
+			p.Notafter = t.Unix()
+			p.RFC3339Notafter = t.Format(time.RFC3339)
+		}
+		times, _ := f[4].([]bigquery.Value)
+		for _, t := range times {
+			if t, ok := t.(time.Time); ok {
+				p.Unixtimearr = append(p.Unixtimearr, t.Unix())
+				p.RFC3339Timearr = append(p.RFC3339Timearr, t.Format(time.RFC3339))
+			}
+		}
+		out = append(out, p)
+	}
+	return out
+}
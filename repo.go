@@ -0,0 +1,596 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/netip"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gidoBOSSftw5731/log"
+)
+
+// repoSource validates a locally synced RPKI repository (an rsync or RRDP
+// mirror laid out as host/path, like rpki-client's cache) against a
+// directory of TALs, instead of trusting a remote validator's JSON.
+type repoSource struct {
+	root string
+	tals string
+}
+
+// localRepo is set when updates should come from a local repository
+var localRepo *repoSource
+
+// repoFromEnv reads RPKI_REPO and RPKI_TALS, it's nil if they aren't set
+func repoFromEnv() (*repoSource, error) {
+	root, tals := os.Getenv("RPKI_REPO"), os.Getenv("RPKI_TALS")
+	if root == "" && tals == "" {
+		return nil, nil
+	}
+	if root == "" || tals == "" {
+		return nil, errors.New("RPKI_REPO and RPKI_TALS need setting together")
+	}
+	return &repoSource{root: root, tals: tals}, nil
+}
+
+func (s *repoSource) String() string {
+	return "file://" + s.root
+}
+
+// load validates the repository and streams the VRPs into staging as
+// they're found, like downloadRARC does, hashing them and archiving them as
+// Routinator style JSON on the way. Only the VRPs and router keys are hashed,
+// the metadata has the time in it and the same repository should hash the
+// same.
+func (s *repoSource) load(ctx context.Context, run *updateRun, staging *stagingWriter) (inputROAArr, error) {
+	run.Fetched = time.Now()
+	doc := inputROAArr{Metadata: inputMetadata{GeneratedTime: run.Fetched.UTC().Format(time.RFC3339)}}
+
+	hash := sha256.New()
+	hashed := json.NewEncoder(hash)
+	out := ioutil.Discard
+	var spool *archiveSpool
+	var err error
+	if archive != nil {
+		spool, err = newArchiveSpool()
+		if err != nil {
+			return doc, err
+		}
+		defer spool.Close()
+		out = spool
+	}
+
+	w, err := newVRPWriter(out, doc.Metadata)
+	if err != nil {
+		return doc, err
+	}
+	doc.RouterKeys, err = validateRepo(s.root, s.tals, run.Fetched, func(r inputROA, dup bool) error {
+		if err := hashed.Encode(r); err != nil {
+			return err
+		}
+		if err := w.roa(r); err != nil {
+			return err
+		}
+		if dup {
+			return staging.AddSource(r)
+		}
+		run.ROAs++
+		return staging.Write([]inputROA{r})
+	})
+	if err != nil {
+		return doc, err
+	}
+	if err := hashed.Encode(doc.RouterKeys); err != nil {
+		return doc, err
+	}
+	if err := w.close(doc.RouterKeys); err != nil {
+		return doc, err
+	}
+	run.PayloadHash = hex.EncodeToString(hash.Sum(nil))
+
+	if spool != nil {
+		run.ArchiveKey, err = spool.store(ctx, run.Fetched, run.PayloadHash)
+		if err != nil {
+			log.Errorln("error archiving VRPs: ", err)
+		}
+	}
+	return doc, nil
+}
+
+// vrpWriter writes a Routinator style document a VRP at a time
+type vrpWriter struct {
+	w   *bufio.Writer
+	enc *json.Encoder
+	n   int
+}
+
+func newVRPWriter(w io.Writer, meta inputMetadata) (*vrpWriter, error) {
+	v := &vrpWriter{w: bufio.NewWriter(w)}
+	v.enc = json.NewEncoder(v.w)
+	v.w.WriteString(`{"metadata":`)
+	if err := v.enc.Encode(meta); err != nil {
+		return nil, err
+	}
+	_, err := v.w.WriteString(`,"roas":[`)
+	return v, err
+}
+
+func (v *vrpWriter) roa(r inputROA) error {
+	if v.n > 0 {
+		v.w.WriteByte(',')
+	}
+	v.n++
+	return v.enc.Encode(r)
+}
+
+// close finishes the document off with the router keys
+func (v *vrpWriter) close(keys []inputRouterKey) error {
+	if keys == nil {
+		keys = []inputRouterKey{}
+	}
+	v.w.WriteString(`],"routerKeys":`)
+	if err := v.enc.Encode(keys); err != nil {
+		return err
+	}
+	v.w.WriteString("}\n")
+	return v.w.Flush()
+}
+
+// tal is a trust anchor locator (RFC 8630)
+type tal struct {
+	name string
+	uris []string
+	spki []byte
+}
+
+func parseTAL(name string, b []byte) (tal, error) {
+	t := tal{name: name}
+	var key strings.Builder
+	inKey := false
+	sc := bufio.NewScanner(bytes.NewReader(b))
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		switch {
+		case strings.HasPrefix(line, "#") && !inKey && len(t.uris) == 0:
+		case line == "":
+			if len(t.uris) > 0 {
+				inKey = true
+			}
+		case inKey:
+			key.WriteString(line)
+		default:
+			t.uris = append(t.uris, line)
+		}
+	}
+	if len(t.uris) == 0 {
+		return t, fmt.Errorf("TAL %v has no URIs", name)
+	}
+	spki, err := base64.StdEncoding.DecodeString(key.String())
+	if err != nil {
+		return t, fmt.Errorf("TAL %v has a bad key: %v", name, err)
+	}
+	t.spki = spki
+	return t, nil
+}
+
+// localPath is where a repository URI is mirrored under root
+func localPath(root, uri string) (string, error) {
+	for _, scheme := range []string{"rsync://", "https://"} {
+		if strings.HasPrefix(uri, scheme) {
+			p := path.Clean("/" + strings.TrimPrefix(uri, scheme))
+			return filepath.Join(root, filepath.FromSlash(p)), nil
+		}
+	}
+	return "", fmt.Errorf("unsupported URI %q", uri)
+}
+
+// repoValidator walks one TA's repository, handing VRPs to emit and
+// collecting router keys
+type repoValidator struct {
+	root string
+	ta   string
+	at   time.Time
+	emit func(r inputROA, dup bool) error
+	// vrps are the VRPs emitted so far, to spot ones from more than one ROA
+	vrps map[vrpKey]bool
+	keys []inputRouterKey
+	// seen stops a CA being walked twice, by SKI
+	seen    map[string]bool
+	invalid int
+	// err is the first error from emit, it stops the walk
+	err error
+}
+
+type vrpKey struct {
+	asn    uint32
+	prefix netip.Prefix
+	maxLen int
+}
+
+// maxDepth is deeper than any real CA hierarchy goes
+const maxDepth = 32
+
+// validateRepo validates every TA in tals against the repository at root as
+// of at, handing each VRP to emit in Routinator's shape as it's found. A VRP
+// that more than one ROA gives is handed over again for each of them, with
+// dup set. It returns the router keys, there aren't many.
+func validateRepo(root, tals string, at time.Time, emit func(r inputROA, dup bool) error) ([]inputRouterKey, error) {
+	files, err := filepath.Glob(filepath.Join(tals, "*.tal"))
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no TALs in %v", tals)
+	}
+	sort.Strings(files)
+
+	var keys []inputRouterKey
+	for _, f := range files {
+		b, err := ioutil.ReadFile(f)
+		if err != nil {
+			return nil, err
+		}
+		t, err := parseTAL(strings.TrimSuffix(filepath.Base(f), ".tal"), b)
+		if err != nil {
+			return nil, err
+		}
+
+		v := &repoValidator{
+			root: root,
+			ta:   t.name,
+			at:   at,
+			emit: emit,
+			vrps: make(map[vrpKey]bool),
+			seen: make(map[string]bool),
+		}
+		err = v.walkTA(t)
+		if v.err != nil {
+			return nil, v.err
+		}
+		if err != nil {
+			// one broken TA shouldn't lose the rest, the outage check will
+			// notice it's missing
+			log.Errorf("TA %v failed: %v", t.name, err)
+			continue
+		}
+		log.Debugf("TA %v: %v VRPs, %v router keys, %v invalid objects",
+			t.name, len(v.vrps), len(v.keys), v.invalid)
+
+		keys = append(keys, v.keys...)
+	}
+	return keys, nil
+}
+
+// walkTA finds the TA certificate from the TAL, checks it and walks
+// everything under it
+func (v *repoValidator) walkTA(t tal) error {
+	var ta *x509.Certificate
+	for _, uri := range t.uris {
+		p, err := localPath(v.root, uri)
+		if err != nil {
+			continue
+		}
+		b, err := ioutil.ReadFile(p)
+		if err != nil {
+			continue
+		}
+		c, err := x509.ParseCertificate(b)
+		if err != nil {
+			return fmt.Errorf("TA certificate %v: %v", uri, err)
+		}
+		ta = c
+		break
+	}
+	if ta == nil {
+		return errors.New("TA certificate isn't in the repository")
+	}
+
+	if !bytes.Equal(ta.RawSubjectPublicKeyInfo, t.spki) {
+		return errors.New("TA certificate doesn't match the TAL's key")
+	}
+	if err := ta.CheckSignatureFrom(ta); err != nil {
+		return fmt.Errorf("TA certificate: %v", err)
+	}
+	if err := v.checkTime(ta); err != nil {
+		return fmt.Errorf("TA certificate: %v", err)
+	}
+	res, err := parseResources(ta)
+	if err != nil {
+		return fmt.Errorf("TA certificate: %v", err)
+	}
+	if res.inherit4 || res.inherit6 || res.inheritAS {
+		return errors.New("TA certificate can't inherit resources")
+	}
+
+	return v.walkCA(ta, res, 0)
+}
+
+func (v *repoValidator) checkTime(c *x509.Certificate) error {
+	if v.at.Before(c.NotBefore) || v.at.After(c.NotAfter) {
+		return fmt.Errorf("not valid at %v", v.at)
+	}
+	return nil
+}
+
+// invalidf logs an object we're ignoring
+func (v *repoValidator) invalidf(format string, args ...interface{}) {
+	v.invalid++
+	log.Debugf("%v: "+format, append([]interface{}{v.ta}, args...)...)
+}
+
+// walkCA processes everything on ca's manifest. res are ca's resolved
+// resources.
+func (v *repoValidator) walkCA(ca *x509.Certificate, res resources, depth int) error {
+	ski := hex.EncodeToString(ca.SubjectKeyId)
+	if v.seen[ski] {
+		// published twice or referenced from somewhere else, it's already
+		// been done (and a loop ends here too)
+		return nil
+	}
+	if depth > maxDepth {
+		return fmt.Errorf("CA %v is too deep", ski)
+	}
+	v.seen[ski] = true
+
+	mftURIs := siaURIs(ca, oidRPKIManifest)
+	if len(mftURIs) == 0 {
+		return fmt.Errorf("CA %v has no manifest", ski)
+	}
+	mftPath, err := localPath(v.root, mftURIs[0])
+	if err != nil {
+		return err
+	}
+	b, err := ioutil.ReadFile(mftPath)
+	if err != nil {
+		return fmt.Errorf("manifest %v: %v", mftURIs[0], err)
+	}
+	obj, err := parseSignedObject(b)
+	if err != nil {
+		return fmt.Errorf("manifest %v: %v", mftURIs[0], err)
+	}
+	if !obj.contentType.Equal(oidManifest) {
+		return fmt.Errorf("manifest %v isn't a manifest", mftURIs[0])
+	}
+	var mft manifestContent
+	if _, err := asn1.Unmarshal(obj.content, &mft); err != nil {
+		return fmt.Errorf("manifest %v: %v", mftURIs[0], err)
+	}
+	if v.at.Before(mft.ThisUpdate) || v.at.After(mft.NextUpdate) {
+		return fmt.Errorf("manifest %v is stale", mftURIs[0])
+	}
+	if !mft.FileHashAlg.Equal(oidSHA256) {
+		return fmt.Errorf("manifest %v uses an unsupported hash", mftURIs[0])
+	}
+
+	// files are relative to the manifest's directory
+	dir := filepath.Dir(mftPath)
+	uriDir := mftURIs[0][:strings.LastIndex(mftURIs[0], "/")+1]
+	read := func(f manifestFile) ([]byte, error) {
+		if strings.ContainsAny(f.Name, "/\\") {
+			return nil, fmt.Errorf("bad file name %q", f.Name)
+		}
+		b, err := ioutil.ReadFile(filepath.Join(dir, f.Name))
+		if err != nil {
+			return nil, err
+		}
+		sum := sha256.Sum256(b)
+		if !bytes.Equal(sum[:], f.Hash.Bytes) {
+			return nil, fmt.Errorf("%v doesn't match the manifest", f.Name)
+		}
+		return b, nil
+	}
+
+	// the CRL first, everything else is checked against it
+	var revoked map[string]bool
+	for _, f := range mft.Files {
+		if !strings.HasSuffix(f.Name, ".crl") {
+			continue
+		}
+		b, err := read(f)
+		if err != nil {
+			return fmt.Errorf("CRL: %v", err)
+		}
+		crl, err := x509.ParseRevocationList(b)
+		if err != nil {
+			return fmt.Errorf("CRL %v: %v", f.Name, err)
+		}
+		if err := crl.CheckSignatureFrom(ca); err != nil {
+			return fmt.Errorf("CRL %v: %v", f.Name, err)
+		}
+		if v.at.After(crl.NextUpdate) {
+			return fmt.Errorf("CRL %v is stale", f.Name)
+		}
+		revoked = make(map[string]bool)
+		for _, r := range crl.RevokedCertificateEntries {
+			revoked[r.SerialNumber.String()] = true
+		}
+	}
+	if revoked == nil {
+		return fmt.Errorf("CA %v has no CRL", ski)
+	}
+
+	// checkEE checks a certificate was issued by ca and is still good
+	checkEE := func(c *x509.Certificate) (resources, error) {
+		if err := c.CheckSignatureFrom(ca); err != nil {
+			return resources{}, err
+		}
+		if err := v.checkTime(c); err != nil {
+			return resources{}, err
+		}
+		if revoked[c.SerialNumber.String()] {
+			return resources{}, errors.New("revoked")
+		}
+		r, err := parseResources(c)
+		if err != nil {
+			return resources{}, err
+		}
+		r = r.resolve(res)
+		if !r.within(res) {
+			return resources{}, errors.New("resources aren't the issuer's")
+		}
+		return r, nil
+	}
+	if _, err := checkEE(obj.ee); err != nil {
+		return fmt.Errorf("manifest %v: %v", mftURIs[0], err)
+	}
+
+	for _, f := range mft.Files {
+		ext := strings.ToLower(filepath.Ext(f.Name))
+		if ext != ".cer" && ext != ".roa" {
+			continue
+		}
+		b, err := read(f)
+		if err != nil {
+			v.invalidf("%v", err)
+			continue
+		}
+
+		switch ext {
+		case ".cer":
+			c, err := x509.ParseCertificate(b)
+			if err != nil {
+				v.invalidf("%v: %v", f.Name, err)
+				continue
+			}
+			r, err := checkEE(c)
+			if err != nil {
+				v.invalidf("%v: %v", f.Name, err)
+				continue
+			}
+			switch {
+			case c.IsCA:
+				if err := v.walkCA(c, r, depth+1); err != nil {
+					v.invalidf("%v: %v", f.Name, err)
+				}
+			case isRouterCert(c):
+				v.routerKey(c, r)
+			}
+
+		case ".roa":
+			v.roa(uriDir+f.Name, b, checkEE)
+		}
+		if v.err != nil {
+			return v.err
+		}
+	}
+	return nil
+}
+
+// roa validates a ROA and adds its VRPs
+func (v *repoValidator) roa(uri string, b []byte, checkEE func(*x509.Certificate) (resources, error)) {
+	obj, err := parseSignedObject(b)
+	if err != nil {
+		v.invalidf("%v: %v", uri, err)
+		return
+	}
+	if !obj.contentType.Equal(oidROA) {
+		v.invalidf("%v isn't a ROA", uri)
+		return
+	}
+	res, err := checkEE(obj.ee)
+	if err != nil {
+		v.invalidf("%v: %v", uri, err)
+		return
+	}
+	var roa roaContent
+	if _, err := asn1.Unmarshal(obj.content, &roa); err != nil {
+		v.invalidf("%v: %v", uri, err)
+		return
+	}
+	if roa.ASID < 0 || roa.ASID > 1<<32-1 {
+		v.invalidf("%v: bad ASN %v", uri, roa.ASID)
+		return
+	}
+
+	// all or nothing, a ROA with any prefix it can't have is invalid
+	var prefixes []netip.Prefix
+	var maxLens []int
+	for _, fam := range roa.Families {
+		family := afiFamily(fam.AFI)
+		if family == 0 {
+			v.invalidf("%v: unknown address family", uri)
+			return
+		}
+		for _, a := range fam.Addresses {
+			addr, err := bitStringAddr(family, a.Address, false)
+			if err != nil {
+				v.invalidf("%v: %v", uri, err)
+				return
+			}
+			p := netip.PrefixFrom(addr, a.Address.BitLength)
+			last, _ := bitStringAddr(family, a.Address, true)
+			maxLen := a.MaxLength
+			if maxLen == -1 {
+				maxLen = p.Bits()
+			}
+			if maxLen < p.Bits() || maxLen > addr.BitLen() {
+				v.invalidf("%v: bad max length %v for %v", uri, maxLen, p)
+				return
+			}
+			if !res.containsAddrs(addr, last) {
+				v.invalidf("%v: %v isn't in the EE certificate", uri, p)
+				return
+			}
+			prefixes = append(prefixes, p)
+			maxLens = append(maxLens, maxLen)
+		}
+	}
+
+	src := inputSource{
+		URI:       uri,
+		SKI:       strings.ToUpper(hex.EncodeToString(obj.ee.SubjectKeyId)),
+		NotBefore: obj.ee.NotBefore,
+		NotAfter:  obj.ee.NotAfter,
+	}
+	for i, p := range prefixes {
+		k := vrpKey{uint32(roa.ASID), p, maxLens[i]}
+		err := v.emit(inputROA{
+			Asn:       fmt.Sprintf("AS%d", roa.ASID),
+			Prefix:    p.String(),
+			MaxLength: maxLens[i],
+			Ta:        v.ta,
+			Source:    []inputSource{src},
+		}, v.vrps[k])
+		if err != nil {
+			v.err = err
+			return
+		}
+		v.vrps[k] = true
+	}
+}
+
+// routerKey adds a BGPsec router certificate's key for each of its ASNs
+func (v *repoValidator) routerKey(c *x509.Certificate, res resources) {
+	key := base64.StdEncoding.EncodeToString(c.RawSubjectPublicKeyInfo)
+	ski := strings.ToUpper(hex.EncodeToString(c.SubjectKeyId))
+	for _, r := range res.asns {
+		// router certificates list ASNs, not big ranges
+		if r.hi-r.lo > 64 {
+			v.invalidf("router certificate %v has too many ASNs", ski)
+			return
+		}
+		for asn := uint64(r.lo); asn <= uint64(r.hi); asn++ {
+			v.keys = append(v.keys, inputRouterKey{
+				Asn:             fmt.Sprintf("AS%d", asn),
+				SKI:             ski,
+				RouterPublicKey: key,
+				Ta:              v.ta,
+			})
+		}
+	}
+}
@@ -0,0 +1,498 @@
+package main
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+)
+
+// the tests build a small repository at test time: a TA, a CA under it, and
+// ROAs under that, each with its manifest and CRL
+
+var testAt = time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+var (
+	testKeysOnce sync.Once
+	testKeys     []*rsa.PrivateKey
+)
+
+// testKey is one of a few RSA keys, they're slow to make
+func testKey(t *testing.T, n int) *rsa.PrivateKey {
+	testKeysOnce.Do(func() {
+		for i := 0; i < 3; i++ {
+			k, err := rsa.GenerateKey(rand.Reader, 2048)
+			if err != nil {
+				panic(err)
+			}
+			testKeys = append(testKeys, k)
+		}
+	})
+	return testKeys[n]
+}
+
+func mustMarshal(t *testing.T, v interface{}) []byte {
+	t.Helper()
+	b, err := asn1.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+// wrap puts DER in a tag, constructed
+func wrap(class, tag int, der []byte) []byte {
+	b, _ := asn1.Marshal(asn1.RawValue{Class: class, Tag: tag, IsCompound: true, Bytes: der})
+	return b
+}
+
+func prefixBits(p netip.Prefix) asn1.BitString {
+	b := p.Addr().AsSlice()
+	n := (p.Bits() + 7) / 8
+	return asn1.BitString{Bytes: b[:n], BitLength: p.Bits()}
+}
+
+// ipExt is an RFC 3779 IP address extension, nil prefixes inherit
+func ipExt(t *testing.T, prefixes ...string) pkix.Extension {
+	type family struct {
+		AFI    []byte
+		Choice asn1.RawValue
+	}
+	var fams []family
+	if prefixes == nil {
+		for _, afi := range []byte{1, 2} {
+			fams = append(fams, family{[]byte{0, afi}, asn1.RawValue{FullBytes: asn1.NullBytes}})
+		}
+	}
+	byAFI := map[byte][]asn1.BitString{}
+	for _, s := range prefixes {
+		p := netip.MustParsePrefix(s)
+		afi := byte(1)
+		if p.Addr().Is6() {
+			afi = 2
+		}
+		byAFI[afi] = append(byAFI[afi], prefixBits(p))
+	}
+	for _, afi := range []byte{1, 2} {
+		if bs, ok := byAFI[afi]; ok {
+			fams = append(fams, family{[]byte{0, afi}, asn1.RawValue{FullBytes: mustMarshal(t, bs)}})
+		}
+	}
+	return pkix.Extension{Id: oidIPAddrBlocks, Critical: true, Value: mustMarshal(t, fams)}
+}
+
+// asExt is an RFC 3779 AS extension for lo-hi, or inherit if lo is -1
+func asExt(t *testing.T, lo, hi int64) pkix.Extension {
+	choice := asn1.NullBytes
+	if lo != -1 {
+		choice = mustMarshal(t, []struct{ Min, Max int64 }{{lo, hi}})
+	}
+	ids := struct{ ASNum asn1.RawValue }{asn1.RawValue{FullBytes: wrap(asn1.ClassContextSpecific, 0, choice)}}
+	return pkix.Extension{Id: oidASIdentifiers, Critical: true, Value: mustMarshal(t, ids)}
+}
+
+// siaExt points at a CA's repository and manifest
+func siaExt(t *testing.T, repo, mft string) pkix.Extension {
+	type ad struct {
+		Method   asn1.ObjectIdentifier
+		Location asn1.RawValue
+	}
+	uri := func(s string) asn1.RawValue {
+		return asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 6, Bytes: []byte(s)}
+	}
+	return pkix.Extension{Id: oidSIA, Value: mustMarshal(t, []ad{
+		{oidCARepository, uri(repo)},
+		{oidRPKIManifest, uri(mft)},
+	})}
+}
+
+// testCA is a CA certificate and what it needs to issue things
+type testCA struct {
+	cert    *x509.Certificate
+	key     *rsa.PrivateKey
+	serial  int64
+	revoked []*big.Int
+}
+
+// testCerts numbers certificates, for unique subjects and SKIs
+var testCerts int
+
+// issue makes a certificate signed by ca, or self-signed if ca is nil
+func issue(t *testing.T, ca *testCA, key *rsa.PrivateKey, isCA bool, exts ...pkix.Extension) *x509.Certificate {
+	t.Helper()
+	testCerts++
+	tmpl := &x509.Certificate{
+		Subject:         pkix.Name{CommonName: fmt.Sprintf("test %v", testCerts)},
+		NotBefore:       testAt.Add(-24 * time.Hour),
+		NotAfter:        testAt.Add(365 * 24 * time.Hour),
+		SubjectKeyId:    []byte(fmt.Sprintf("%020d", testCerts)),
+		ExtraExtensions: exts,
+		KeyUsage:        x509.KeyUsageDigitalSignature,
+	}
+	if isCA {
+		tmpl.IsCA = true
+		tmpl.BasicConstraintsValid = true
+		tmpl.KeyUsage = x509.KeyUsageCertSign | x509.KeyUsageCRLSign
+	}
+	parent, signer := tmpl, key
+	if ca != nil {
+		ca.serial++
+		tmpl.SerialNumber = big.NewInt(ca.serial)
+		parent, signer = ca.cert, ca.key
+	} else {
+		tmpl.SerialNumber = big.NewInt(1)
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, parent, &key.PublicKey, signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+// signObject wraps content in CMS signed data by a new EE certificate from
+// ca, returning the object and the EE certificate
+func signObject(t *testing.T, ca *testCA, ctype asn1.ObjectIdentifier, content []byte, badSig bool, exts ...pkix.Extension) ([]byte, *x509.Certificate) {
+	t.Helper()
+	key := testKey(t, 2)
+	ee := issue(t, ca, key, false, exts...)
+
+	type attribute struct {
+		Type   asn1.ObjectIdentifier
+		Values asn1.RawValue
+	}
+	set := func(der []byte) asn1.RawValue {
+		return asn1.RawValue{Class: asn1.ClassUniversal, Tag: asn1.TagSet, IsCompound: true, Bytes: der}
+	}
+	sum := sha256.Sum256(content)
+	attrs := append(
+		mustMarshal(t, attribute{oidContentType, set(mustMarshal(t, ctype))}),
+		mustMarshal(t, attribute{oidMessageDigest, set(mustMarshal(t, sum[:]))})...)
+
+	signed := sha256.Sum256(mustMarshal(t, set(attrs)))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, signed[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if badSig {
+		sig[len(sig)-1] ^= 0xff
+	}
+
+	si := signerInfo{
+		Version:            3,
+		SID:                asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, Bytes: ee.SubjectKeyId},
+		DigestAlgorithm:    pkix.AlgorithmIdentifier{Algorithm: oidSHA256},
+		SignedAttrs:        asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: attrs},
+		SignatureAlgorithm: pkix.AlgorithmIdentifier{Algorithm: oidRSA},
+		Signature:          sig,
+	}
+	sd := signedData{
+		Version:          3,
+		DigestAlgorithms: set(mustMarshal(t, pkix.AlgorithmIdentifier{Algorithm: oidSHA256})),
+		EncapContent:     encapContentInfo{EContentType: ctype, EContent: content},
+		Certificates:     asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: ee.Raw},
+		SignerInfos:      set(mustMarshal(t, si)),
+	}
+	return mustMarshal(t, contentInfo{
+		ContentType: oidSignedData,
+		Content:     asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: mustMarshal(t, sd)},
+	}), ee
+}
+
+// testROA makes a ROA for asn and prefixes (as prefix or prefix-maxlen)
+func testROA(t *testing.T, ca *testCA, asn int64, prefixes []string, badSig bool, exts ...pkix.Extension) ([]byte, *x509.Certificate) {
+	t.Helper()
+	roa := roaContent{ASID: asn}
+	for _, afi := range []byte{1, 2} {
+		fam := roaFamily{AFI: []byte{0, afi}}
+		for _, s := range prefixes {
+			maxLen := -1
+			if i := bytes.LastIndexByte([]byte(s), '-'); i != -1 {
+				fmt.Sscan(s[i+1:], &maxLen)
+				s = s[:i]
+			}
+			p := netip.MustParsePrefix(s)
+			if p.Addr().Is6() == (afi == 2) {
+				fam.Addresses = append(fam.Addresses, roaAddress{prefixBits(p), maxLen})
+			}
+		}
+		if len(fam.Addresses) > 0 {
+			roa.Families = append(roa.Families, fam)
+		}
+	}
+	if exts == nil {
+		exts = []pkix.Extension{ipExt(t), asExt(t, -1, 0)}
+	}
+	return signObject(t, ca, oidROA, mustMarshal(t, roa), badSig, exts...)
+}
+
+// publish writes a CA's CRL, manifest and files into dir. thisUpdate and
+// nextUpdate are the manifest's. mangle, if set, changes a file after it's
+// been hashed.
+func publish(t *testing.T, ca *testCA, dir, name string, files map[string][]byte, nextUpdate time.Time, mangle string) {
+	t.Helper()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	var entries []x509.RevocationListEntry
+	for _, s := range ca.revoked {
+		entries = append(entries, x509.RevocationListEntry{SerialNumber: s, RevocationTime: testAt.Add(-time.Hour)})
+	}
+	crl, err := x509.CreateRevocationList(rand.Reader, &x509.RevocationList{
+		Number:                    big.NewInt(1),
+		ThisUpdate:                testAt.Add(-time.Hour),
+		NextUpdate:                testAt.Add(24 * time.Hour),
+		RevokedCertificateEntries: entries,
+	}, ca.cert, ca.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	files[name+".crl"] = crl
+
+	var names []string
+	for n := range files {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	mft := manifestContent{
+		Number:      big.NewInt(1),
+		ThisUpdate:  testAt.Add(-time.Hour),
+		NextUpdate:  nextUpdate,
+		FileHashAlg: oidSHA256,
+	}
+	for _, n := range names {
+		sum := sha256.Sum256(files[n])
+		mft.Files = append(mft.Files, manifestFile{n, asn1.BitString{Bytes: sum[:], BitLength: 256}})
+	}
+	obj, _ := signObject(t, ca, oidManifest, mustMarshal(t, mft), false, ipExt(t), asExt(t, -1, 0))
+	files[name+".mft"] = obj
+
+	for n, b := range files {
+		if n == mangle {
+			b = append(append([]byte{}, b...), 0)
+		}
+		if err := ioutil.WriteFile(filepath.Join(dir, n), b, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// repoCase is what's wrong with the repository's bad.roa, or its CA
+type repoCase struct {
+	badSig       bool
+	revoked      bool
+	staleMft     bool
+	hashMismatch bool
+	eeOutside    bool
+	roaOutside   bool
+	caTwice      bool
+}
+
+// buildRepo makes a repository and TAL directory. The CA has 192.0.2.0/24,
+// 2001:db8::/32 and AS64496-AS64511, and publishes good.roa, dup.roa (the
+// same VRP as good.roa's first one) and bad.roa.
+func buildRepo(t *testing.T, c repoCase) (string, string) {
+	t.Helper()
+	dir := t.TempDir()
+	root, tals := filepath.Join(dir, "repo"), filepath.Join(dir, "tals")
+	base := "rsync://rpki.example/repo/"
+	local := filepath.Join(root, "rpki.example", "repo")
+
+	ta := &testCA{key: testKey(t, 0)}
+	ta.cert = issue(t, nil, ta.key, true,
+		ipExt(t, "0.0.0.0/0", "::/0"), asExt(t, 0, 1<<32-1),
+		siaExt(t, base+"ta/", base+"ta/ta.mft"))
+
+	ca := &testCA{key: testKey(t, 1)}
+	ca.cert = issue(t, ta, ca.key, true,
+		ipExt(t, "192.0.2.0/24", "2001:db8::/32"), asExt(t, 64496, 64511),
+		siaExt(t, base+"ca/", base+"ca/ca.mft"))
+
+	taFiles := map[string][]byte{"ca.cer": ca.cert.Raw}
+	if c.caTwice {
+		taFiles["ca-again.cer"] = ca.cert.Raw
+	}
+	publish(t, ta, filepath.Join(local, "ta"), "ta", taFiles, testAt.Add(24*time.Hour), "")
+
+	good, _ := testROA(t, ca, 64496, []string{"192.0.2.0/24", "2001:db8::/32-48"}, false)
+	dup, _ := testROA(t, ca, 64496, []string{"192.0.2.0/24"}, false)
+
+	badPrefix := "192.0.2.128/25"
+	var exts []pkix.Extension
+	switch {
+	case c.eeOutside:
+		badPrefix = "198.51.100.0/24"
+		exts = []pkix.Extension{ipExt(t, badPrefix), asExt(t, -1, 0)}
+	case c.roaOutside:
+		badPrefix = "198.51.100.0/24"
+	}
+	bad, badEE := testROA(t, ca, 64497, []string{badPrefix}, c.badSig, exts...)
+	if c.revoked {
+		ca.revoked = append(ca.revoked, badEE.SerialNumber)
+	}
+
+	next := testAt.Add(24 * time.Hour)
+	if c.staleMft {
+		next = testAt.Add(-time.Minute)
+	}
+	mangle := ""
+	if c.hashMismatch {
+		mangle = "bad.roa"
+	}
+	publish(t, ca, filepath.Join(local, "ca"), "ca",
+		map[string][]byte{"good.roa": good, "dup.roa": dup, "bad.roa": bad}, next, mangle)
+
+	if err := ioutil.WriteFile(filepath.Join(local, "ta.cer"), ta.cert.Raw, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(tals, 0755); err != nil {
+		t.Fatal(err)
+	}
+	tal := fmt.Sprintf("# test TA\n%vta.cer\n\n%v\n", base,
+		base64.StdEncoding.EncodeToString(ta.cert.RawSubjectPublicKeyInfo))
+	if err := ioutil.WriteFile(filepath.Join(tals, "test.tal"), []byte(tal), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return root, tals
+}
+
+// validateVRPs runs the validator, returning the VRPs it found as strings and
+// how many of them were dups
+func validateVRPs(t *testing.T, root, tals string) ([]string, int) {
+	t.Helper()
+	var vrps []string
+	dups := 0
+	_, err := validateRepo(root, tals, testAt, func(r inputROA, dup bool) error {
+		if dup {
+			dups++
+			return nil
+		}
+		vrps = append(vrps, fmt.Sprintf("%v %v %v %v", r.Asn, r.Prefix, r.MaxLength, r.Ta))
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(vrps)
+	return vrps, dups
+}
+
+func TestValidateRepo(t *testing.T) {
+	good := []string{"AS64496 192.0.2.0/24 24 test", "AS64496 2001:db8::/32 48 test"}
+	bad := "AS64497 192.0.2.128/25 25 test"
+
+	for _, tc := range []struct {
+		name string
+		c    repoCase
+		want []string
+	}{
+		{"valid", repoCase{}, append(good, bad)},
+		{"CA published twice", repoCase{caTwice: true}, append(good, bad)},
+		{"bad signature", repoCase{badSig: true}, good},
+		{"revoked EE", repoCase{revoked: true}, good},
+		{"hash mismatch", repoCase{hashMismatch: true}, good},
+		{"EE resources outside the CA's", repoCase{eeOutside: true}, good},
+		{"ROA prefix outside the EE's", repoCase{roaOutside: true}, good},
+		{"stale manifest", repoCase{staleMft: true}, nil},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			root, tals := buildRepo(t, tc.c)
+			got, dups := validateVRPs(t, root, tals)
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("got VRPs %q, want %q", got, tc.want)
+			}
+			// dup.roa only repeats one of good.roa's
+			wantDups := 1
+			if tc.want == nil {
+				wantDups = 0
+			}
+			if dups != wantDups {
+				t.Errorf("got %v repeated VRPs, want %v", dups, wantDups)
+			}
+		})
+	}
+}
+
+func TestValidateRepoWrongTAL(t *testing.T) {
+	root, tals := buildRepo(t, repoCase{})
+	// a TAL for some other key: the TA is skipped, not trusted
+	other := fmt.Sprintf("rsync://rpki.example/repo/ta.cer\n\n%v\n",
+		base64.StdEncoding.EncodeToString(mustMarshalPub(t, &testKey(t, 1).PublicKey)))
+	if err := ioutil.WriteFile(filepath.Join(tals, "test.tal"), []byte(other), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := validateVRPs(t, root, tals); len(got) != 0 {
+		t.Errorf("got VRPs %q from an untrusted TA", got)
+	}
+}
+
+func mustMarshalPub(t *testing.T, pub crypto.PublicKey) []byte {
+	t.Helper()
+	b, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+// what's hashed and archived has to decode like a validator's JSON
+func TestVRPWriter(t *testing.T) {
+	var buf bytes.Buffer
+	meta := inputMetadata{GeneratedTime: testAt.Format(time.RFC3339)}
+	w, err := newVRPWriter(&buf, meta)
+	if err != nil {
+		t.Fatal(err)
+	}
+	roas := []inputROA{
+		{Asn: "AS64496", Prefix: "192.0.2.0/24", MaxLength: 24, Ta: "test"},
+		{Asn: "AS64497", Prefix: "2001:db8::/32", MaxLength: 48, Ta: "test",
+			Source: []inputSource{{URI: "rsync://rpki.example/repo/ca/good.roa", SKI: "AB"}}},
+	}
+	for _, r := range roas {
+		if err := w.roa(r); err != nil {
+			t.Fatal(err)
+		}
+	}
+	keys := []inputRouterKey{{Asn: "AS64496", SKI: "CD", RouterPublicKey: "key", Ta: "test"}}
+	if err := w.close(keys); err != nil {
+		t.Fatal(err)
+	}
+
+	var got []inputROA
+	doc, n, err := decodeRARC(&buf, 1, func(rs []inputROA) error {
+		got = append(got, rs...)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != len(roas) || !reflect.DeepEqual(got, roas) {
+		t.Errorf("got %+v, want %+v", got, roas)
+	}
+	if !reflect.DeepEqual(doc.RouterKeys, keys) {
+		t.Errorf("got router keys %+v, want %+v", doc.RouterKeys, keys)
+	}
+	if at, ok := doc.Metadata.generatedAt(); !ok || !at.Equal(testAt) {
+		t.Errorf("got generated time %v, want %v", at, testAt)
+	}
+}
@@ -0,0 +1,211 @@
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/bigquery"
+	pb "github.com/gidoBOSSftw5731/Historical-ROA/proto"
+	"github.com/gidoBOSSftw5731/log"
+	"google.golang.org/api/iterator"
+)
+
+// inputRouterKey is a BGPsec router key from a validator, in Routinator's
+// shape
+type inputRouterKey struct {
+	Asn             string `json:"asn"`
+	SKI             string `json:"SKI"`
+	RouterPublicKey string `json:"routerPublicKey"`
+	Ta              string `json:"ta,omitempty"`
+}
+
+// UnmarshalJSON takes Routinator's {"asn": "AS64496", "SKI": ...,
+// "routerPublicKey": ...} and rpki-client's {"asn": 64496, "ski": ...,
+// "pubkey": ...}.
+func (k *inputRouterKey) UnmarshalJSON(b []byte) error {
+	type plain inputRouterKey
+	var v struct {
+		plain
+		Asn    json.RawMessage `json:"asn"`
+		Pubkey string          `json:"pubkey"`
+	}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	*k = inputRouterKey(v.plain)
+	if k.RouterPublicKey == "" {
+		k.RouterPublicKey = v.Pubkey
+	}
+
+	var err error
+	k.Asn, err = jsonASN(v.Asn)
+	return err
+}
+
+// routerKeyRow is a row of routerkeys_arr, with the same history as roaRow
+type routerKeyRow struct {
+	Asnum          uint32      `bigquery:"asnum"`
+	SKI            string      `bigquery:"ski"`
+	Pubkey         string      `bigquery:"pubkey"`
+	Ta             string      `bigquery:"ta"`
+	Inserttimes    []time.Time `bigquery:"inserttimes"`
+	Generatedtimes []time.Time `bigquery:"generatedtimes"`
+}
+
+// routerKeyParam is a router key to merge, as a query parameter
+type routerKeyParam struct {
+	Asnum  int64
+	SKI    string
+	Pubkey string
+	Ta     string
+}
+
+// normaliseRouterKey parses the ASN and SKI, and puts the key in standard
+// base64 since validators differ
+func normaliseRouterKey(k inputRouterKey) (routerKeyParam, error) {
+	asn, err := parseASN(k.Asn)
+	if err != nil {
+		return routerKeyParam{}, err
+	}
+
+	ski, err := normaliseSKI(k.SKI)
+	if err != nil {
+		return routerKeyParam{}, err
+	}
+
+	pub := strings.TrimSpace(k.RouterPublicKey)
+	der, err := base64.StdEncoding.DecodeString(pub)
+	if err != nil {
+		der, err = base64.RawURLEncoding.DecodeString(strings.TrimRight(pub, "="))
+	}
+	if err != nil || len(der) == 0 {
+		return routerKeyParam{}, fmt.Errorf("bad router key for SKI %q", k.SKI)
+	}
+
+	return routerKeyParam{
+		Asnum:  int64(asn),
+		SKI:    ski,
+		Pubkey: base64.StdEncoding.EncodeToString(der),
+		Ta:     k.Ta,
+	}, nil
+}
+
+// mergeRouterKeys adds an update's router keys to routerkeys_arr, like
+// mergeASPAs
+func mergeRouterKeys(ctx context.Context, keys []inputRouterKey, fetched, generated time.Time) (int, error) {
+	var params []routerKeyParam
+	seen := make(map[routerKeyParam]bool)
+	for _, k := range keys {
+		p, err := normaliseRouterKey(k)
+		if err != nil {
+			log.Traceln("skipping bad router key ", k, ": ", err)
+			continue
+		}
+		if !seen[p] {
+			seen[p] = true
+			params = append(params, p)
+		}
+	}
+	if len(params) == 0 {
+		return 0, nil
+	}
+
+	if _, err := ensureTable(ctx, "routerkeys_arr", routerKeyRow{}); err != nil {
+		return 0, fmt.Errorf("error updating routerkeys_arr: %v", err)
+	}
+
+	query := client.Query(`MERGE historical.routerkeys_arr arr
+	USING (SELECT * FROM UNNEST(@keys)) k
+	ON arr.asnum = k.Asnum AND arr.ski = k.SKI
+	AND arr.pubkey = k.Pubkey AND arr.ta = k.Ta
+	WHEN MATCHED THEN
+		UPDATE SET inserttimes = ` + addTimeSQL("arr.inserttimes") + `,
+		generatedtimes = ` + addGeneratedSQL("arr.inserttimes", "arr.generatedtimes") + `
+	WHEN NOT MATCHED BY TARGET THEN
+		INSERT (asnum, ski, pubkey, ta, inserttimes, generatedtimes)
+		VALUES (k.Asnum, k.SKI, k.Pubkey, k.Ta, [@fetched], [@generated])`)
+	query.Parameters = []bigquery.QueryParameter{
+		{Name: "keys", Value: params},
+		{Name: "fetched", Value: fetched},
+		{Name: "generated", Value: generated},
+	}
+	_, err := runQuery(ctx, query)
+	return len(params), err
+}
+
+// routerKeysAPI lists the history of router keys for an ASN (or a range of
+// them) or with an SKI.
+//
+//	/api/routerkeys?asn=AS64496
+//	/api/routerkeys?ski=0123456789ABCDEF0123456789ABCDEF01234567
+func routerKeysAPI(w http.ResponseWriter, r *http.Request) {
+	var where string
+	var params []bigquery.QueryParameter
+	switch {
+	case r.FormValue("asn") != "":
+		lo, hi, err := parseASNRange(r.FormValue("asn"))
+		if err != nil {
+			ErrorHandler(w, r, 400, "Bad ASN", err)
+			return
+		}
+		where = "asnum BETWEEN @asnlo AND @asnhi"
+		params = append(params,
+			bigquery.QueryParameter{Name: "asnlo", Value: int64(lo)},
+			bigquery.QueryParameter{Name: "asnhi", Value: int64(hi)})
+	case r.FormValue("ski") != "":
+		ski, err := normaliseSKI(r.FormValue("ski"))
+		if err != nil {
+			ErrorHandler(w, r, 400, "Bad SKI", err)
+			return
+		}
+		where = "ski = @ski"
+		params = append(params, bigquery.QueryParameter{Name: "ski", Value: ski})
+	default:
+		ErrorHandler(w, r, 400, "Need an ASN or SKI", nil)
+		return
+	}
+	if tas := parseTAs(r.FormValue("ta")); len(tas) > 0 {
+		cond, param := taCondition(tas)
+		where += " AND " + cond
+		params = append(params, param)
+	}
+
+	query := client.Query(`SELECT asnum, ski, pubkey, ta, inserttimes
+	FROM historical.routerkeys_arr WHERE ` + where)
+	query.Parameters = params
+	it, err := runQuery(r.Context(), query)
+	if err != nil {
+		ErrorHandler(w, r, 500, "Error with query", err)
+		return
+	}
+
+	var out pb.RouterKeyArr
+	for {
+		var row routerKeyRow
+		err := it.Next(&row)
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			ErrorHandler(w, r, 500, "Error with query", err)
+			return
+		}
+
+		k := &pb.RouterKey{
+			ASN:    fmt.Sprintf("AS%d", row.Asnum),
+			Asnum:  row.Asnum,
+			SKI:    row.SKI,
+			Pubkey: row.Pubkey,
+			Ta:     row.Ta,
+		}
+		k.Unixtimearr, k.RFC3339Timearr = timeArrays(row.Inserttimes)
+		out.Keys = append(out.Keys, k)
+	}
+
+	writeProto(w, &out)
+}
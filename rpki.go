@@ -0,0 +1,433 @@
+package main
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/netip"
+	"time"
+)
+
+// OIDs from RFC 3779, RFC 5652, RFC 6487, RFC 6488, RFC 9286 and RFC 9582
+var (
+	oidSignedData      = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 2}
+	oidContentType     = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 3}
+	oidMessageDigest   = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 4}
+	oidSHA256          = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 1}
+	oidRSA             = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 1, 1}
+	oidSHA256WithRSA   = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 1, 11}
+	oidROA             = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 16, 1, 24}
+	oidManifest        = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 16, 1, 26}
+	oidIPAddrBlocks    = asn1.ObjectIdentifier{1, 3, 6, 1, 5, 5, 7, 1, 7}
+	oidASIdentifiers   = asn1.ObjectIdentifier{1, 3, 6, 1, 5, 5, 7, 1, 8}
+	oidSIA             = asn1.ObjectIdentifier{1, 3, 6, 1, 5, 5, 7, 1, 11}
+	oidCARepository    = asn1.ObjectIdentifier{1, 3, 6, 1, 5, 5, 7, 48, 5}
+	oidRPKIManifest    = asn1.ObjectIdentifier{1, 3, 6, 1, 5, 5, 7, 48, 10}
+	oidBGPsecRouterEKU = asn1.ObjectIdentifier{1, 3, 6, 1, 5, 5, 7, 3, 30}
+)
+
+// CMS SignedData, only as much as RPKI signed objects use (RFC 6488)
+type contentInfo struct {
+	ContentType asn1.ObjectIdentifier
+	Content     asn1.RawValue `asn1:"explicit,tag:0"`
+}
+
+type signedData struct {
+	Version          int
+	DigestAlgorithms asn1.RawValue
+	EncapContent     encapContentInfo
+	Certificates     asn1.RawValue `asn1:"optional,tag:0"`
+	CRLs             asn1.RawValue `asn1:"optional,tag:1"`
+	SignerInfos      asn1.RawValue
+}
+
+type encapContentInfo struct {
+	EContentType asn1.ObjectIdentifier
+	EContent     []byte `asn1:"explicit,tag:0"`
+}
+
+type signerInfo struct {
+	Version            int
+	SID                asn1.RawValue
+	DigestAlgorithm    pkix.AlgorithmIdentifier
+	SignedAttrs        asn1.RawValue `asn1:"tag:0"`
+	SignatureAlgorithm pkix.AlgorithmIdentifier
+	Signature          []byte
+}
+
+type attribute struct {
+	Type   asn1.ObjectIdentifier
+	Values asn1.RawValue
+}
+
+// signedObject is a verified RPKI signed object: its content and the EE
+// certificate that signed it. The EE certificate itself still has to be
+// checked against its issuer.
+type signedObject struct {
+	contentType asn1.ObjectIdentifier
+	content     []byte
+	ee          *x509.Certificate
+}
+
+// parseSignedObject parses a CMS signed object and checks it was signed by
+// the EE certificate inside it
+func parseSignedObject(der []byte) (*signedObject, error) {
+	var ci contentInfo
+	if rest, err := asn1.Unmarshal(der, &ci); err != nil {
+		return nil, err
+	} else if len(rest) > 0 {
+		return nil, errors.New("trailing data")
+	}
+	if !ci.ContentType.Equal(oidSignedData) {
+		return nil, fmt.Errorf("not signed data: %v", ci.ContentType)
+	}
+
+	var sd signedData
+	if _, err := asn1.Unmarshal(ci.Content.Bytes, &sd); err != nil {
+		return nil, fmt.Errorf("signed data: %v", err)
+	}
+	ee, err := x509.ParseCertificate(sd.Certificates.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("EE certificate: %v", err)
+	}
+
+	var si signerInfo
+	if rest, err := asn1.Unmarshal(sd.SignerInfos.Bytes, &si); err != nil {
+		return nil, fmt.Errorf("signer info: %v", err)
+	} else if len(rest) > 0 {
+		return nil, errors.New("more than one signer")
+	}
+	if !bytes.Equal(si.SID.Bytes, ee.SubjectKeyId) {
+		return nil, errors.New("signer isn't the EE certificate")
+	}
+	if !si.DigestAlgorithm.Algorithm.Equal(oidSHA256) {
+		return nil, fmt.Errorf("unsupported digest %v", si.DigestAlgorithm.Algorithm)
+	}
+	if alg := si.SignatureAlgorithm.Algorithm; !alg.Equal(oidRSA) && !alg.Equal(oidSHA256WithRSA) {
+		return nil, fmt.Errorf("unsupported signature %v", alg)
+	}
+
+	// the signed attributes have to say what and whose content this is
+	var digest, ctype []byte
+	for rest := si.SignedAttrs.Bytes; len(rest) > 0; {
+		var a attribute
+		var err error
+		if rest, err = asn1.Unmarshal(rest, &a); err != nil {
+			return nil, fmt.Errorf("signed attributes: %v", err)
+		}
+		switch {
+		case a.Type.Equal(oidMessageDigest):
+			digest = a.Values.Bytes
+		case a.Type.Equal(oidContentType):
+			ctype = a.Values.Bytes
+		}
+	}
+	var want []byte
+	sum := sha256.Sum256(sd.EncapContent.EContent)
+	if want, err = asn1.Marshal(sum[:]); err != nil || !bytes.Equal(digest, want) {
+		return nil, errors.New("message digest doesn't match")
+	}
+	if want, err = asn1.Marshal(sd.EncapContent.EContentType); err != nil || !bytes.Equal(ctype, want) {
+		return nil, errors.New("content type doesn't match")
+	}
+
+	// the signature is over the attributes as a SET, not [0]
+	attrs := append([]byte{}, si.SignedAttrs.FullBytes...)
+	attrs[0] = 0x31
+	pub, ok := ee.PublicKey.(*rsa.PublicKey)
+	if !ok {
+		return nil, errors.New("EE key isn't RSA")
+	}
+	sum = sha256.Sum256(attrs)
+	if err := rsa.VerifyPKCS1v15(pub, crypto.SHA256, sum[:], si.Signature); err != nil {
+		return nil, fmt.Errorf("bad signature: %v", err)
+	}
+
+	return &signedObject{
+		contentType: sd.EncapContent.EContentType,
+		content:     sd.EncapContent.EContent,
+		ee:          ee,
+	}, nil
+}
+
+// roaContent is a ROA's eContent (RFC 9582)
+type roaContent struct {
+	Version  int `asn1:"optional,explicit,default:0,tag:0"`
+	ASID     int64
+	Families []roaFamily
+}
+
+type roaFamily struct {
+	AFI       []byte
+	Addresses []roaAddress
+}
+
+type roaAddress struct {
+	Address   asn1.BitString
+	MaxLength int `asn1:"optional,default:-1"`
+}
+
+// manifestContent is a manifest's eContent (RFC 9286)
+type manifestContent struct {
+	Version     int `asn1:"optional,explicit,default:0,tag:0"`
+	Number      *big.Int
+	ThisUpdate  time.Time `asn1:"generalized"`
+	NextUpdate  time.Time `asn1:"generalized"`
+	FileHashAlg asn1.ObjectIdentifier
+	Files       []manifestFile
+}
+
+type manifestFile struct {
+	Name string `asn1:"ia5"`
+	Hash asn1.BitString
+}
+
+// addrRange is a range of addresses from an RFC 3779 extension or a ROA
+type addrRange struct {
+	lo, hi netip.Addr
+}
+
+type asRange struct {
+	lo, hi uint32
+}
+
+// resources are the IP and AS resources on a certificate. Inherited ones
+// are filled in from the issuer by resolve.
+type resources struct {
+	addrs     []addrRange
+	asns      []asRange
+	inherit4  bool
+	inherit6  bool
+	inheritAS bool
+}
+
+// afiFamily is 4 or 6 for an RFC 3779 address family, or 0
+func afiFamily(afi []byte) int {
+	if len(afi) < 2 || afi[0] != 0 {
+		return 0
+	}
+	switch afi[1] {
+	case 1:
+		return 4
+	case 2:
+		return 6
+	}
+	return 0
+}
+
+// bitStringAddr turns the leading bits of an address into an address of the
+// family, with the rest of the bits set to fill
+func bitStringAddr(family int, bs asn1.BitString, fill bool) (netip.Addr, error) {
+	n := 4
+	if family == 6 {
+		n = 16
+	}
+	if len(bs.Bytes) > n || bs.BitLength > n*8 {
+		return netip.Addr{}, errors.New("address too long")
+	}
+
+	b := make([]byte, n)
+	copy(b, bs.Bytes)
+	for i := bs.BitLength; i < n*8; i++ {
+		if fill {
+			b[i/8] |= 0x80 >> uint(i%8)
+		} else {
+			b[i/8] &^= 0x80 >> uint(i%8)
+		}
+	}
+	addr, _ := netip.AddrFromSlice(b)
+	return addr, nil
+}
+
+// parseResources reads the RFC 3779 extensions on c
+func parseResources(c *x509.Certificate) (resources, error) {
+	var res resources
+	for _, ext := range c.Extensions {
+		switch {
+		case ext.Id.Equal(oidIPAddrBlocks):
+			var families []struct {
+				AFI    []byte
+				Choice asn1.RawValue
+			}
+			if _, err := asn1.Unmarshal(ext.Value, &families); err != nil {
+				return res, fmt.Errorf("IP resources: %v", err)
+			}
+			for _, f := range families {
+				family := afiFamily(f.AFI)
+				if family == 0 {
+					continue
+				}
+				if f.Choice.Tag == asn1.TagNull {
+					if family == 4 {
+						res.inherit4 = true
+					} else {
+						res.inherit6 = true
+					}
+					continue
+				}
+				for rest := f.Choice.Bytes; len(rest) > 0; {
+					var v asn1.RawValue
+					var err error
+					if rest, err = asn1.Unmarshal(rest, &v); err != nil {
+						return res, fmt.Errorf("IP resources: %v", err)
+					}
+					var lo, hi asn1.BitString
+					if v.Tag == asn1.TagBitString {
+						if _, err := asn1.Unmarshal(v.FullBytes, &lo); err != nil {
+							return res, fmt.Errorf("IP resources: %v", err)
+						}
+						hi = lo
+					} else {
+						var r struct{ Min, Max asn1.BitString }
+						if _, err := asn1.Unmarshal(v.FullBytes, &r); err != nil {
+							return res, fmt.Errorf("IP resources: %v", err)
+						}
+						lo, hi = r.Min, r.Max
+					}
+					l, err := bitStringAddr(family, lo, false)
+					if err != nil {
+						return res, err
+					}
+					h, err := bitStringAddr(family, hi, true)
+					if err != nil {
+						return res, err
+					}
+					res.addrs = append(res.addrs, addrRange{l, h})
+				}
+			}
+
+		case ext.Id.Equal(oidASIdentifiers):
+			var ids struct {
+				ASNum asn1.RawValue `asn1:"optional,explicit,tag:0"`
+				RDI   asn1.RawValue `asn1:"optional,explicit,tag:1"`
+			}
+			if _, err := asn1.Unmarshal(ext.Value, &ids); err != nil {
+				return res, fmt.Errorf("AS resources: %v", err)
+			}
+			if len(ids.ASNum.FullBytes) == 0 {
+				continue
+			}
+			// a RawValue gets the explicit tag too, the choice is inside it
+			var choice asn1.RawValue
+			if _, err := asn1.Unmarshal(ids.ASNum.Bytes, &choice); err != nil {
+				return res, fmt.Errorf("AS resources: %v", err)
+			}
+			if choice.Tag == asn1.TagNull {
+				res.inheritAS = true
+				continue
+			}
+			for rest := choice.Bytes; len(rest) > 0; {
+				var v asn1.RawValue
+				var err error
+				if rest, err = asn1.Unmarshal(rest, &v); err != nil {
+					return res, fmt.Errorf("AS resources: %v", err)
+				}
+				var r struct{ Min, Max int64 }
+				if v.Tag == asn1.TagInteger {
+					_, err = asn1.Unmarshal(v.FullBytes, &r.Min)
+					r.Max = r.Min
+				} else {
+					_, err = asn1.Unmarshal(v.FullBytes, &r)
+				}
+				if err != nil || r.Min < 0 || r.Max > 1<<32-1 || r.Max < r.Min {
+					return res, fmt.Errorf("bad AS resource")
+				}
+				res.asns = append(res.asns, asRange{uint32(r.Min), uint32(r.Max)})
+			}
+		}
+	}
+	return res, nil
+}
+
+// resolve fills in anything res inherits from its issuer's resources
+func (res resources) resolve(issuer resources) resources {
+	out := resources{asns: res.asns}
+	for _, r := range res.addrs {
+		out.addrs = append(out.addrs, r)
+	}
+	for _, r := range issuer.addrs {
+		if (res.inherit4 && r.lo.Is4()) || (res.inherit6 && r.lo.Is6()) {
+			out.addrs = append(out.addrs, r)
+		}
+	}
+	if res.inheritAS {
+		out.asns = issuer.asns
+	}
+	return out
+}
+
+func (res resources) containsAddrs(lo, hi netip.Addr) bool {
+	for _, r := range res.addrs {
+		if r.lo.BitLen() == lo.BitLen() && r.lo.Compare(lo) <= 0 && hi.Compare(r.hi) <= 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func (res resources) containsASNs(lo, hi uint32) bool {
+	for _, r := range res.asns {
+		if r.lo <= lo && hi <= r.hi {
+			return true
+		}
+	}
+	return false
+}
+
+// within says whether every resource in res is also in issuer, both
+// resolved
+func (res resources) within(issuer resources) bool {
+	for _, r := range res.addrs {
+		if !issuer.containsAddrs(r.lo, r.hi) {
+			return false
+		}
+	}
+	for _, r := range res.asns {
+		if !issuer.containsASNs(r.lo, r.hi) {
+			return false
+		}
+	}
+	return true
+}
+
+// siaURIs returns the URIs in c's subject information access extension for
+// an access method
+func siaURIs(c *x509.Certificate, method asn1.ObjectIdentifier) []string {
+	var uris []string
+	for _, ext := range c.Extensions {
+		if !ext.Id.Equal(oidSIA) {
+			continue
+		}
+		var ads []struct {
+			Method   asn1.ObjectIdentifier
+			Location asn1.RawValue
+		}
+		if _, err := asn1.Unmarshal(ext.Value, &ads); err != nil {
+			return nil
+		}
+		for _, ad := range ads {
+			// uniformResourceIdentifier [6] IA5String
+			if ad.Method.Equal(method) && ad.Location.Class == asn1.ClassContextSpecific && ad.Location.Tag == 6 {
+				uris = append(uris, string(ad.Location.Bytes))
+			}
+		}
+	}
+	return uris
+}
+
+// isRouterCert says whether c is a BGPsec router certificate (RFC 8209)
+func isRouterCert(c *x509.Certificate) bool {
+	for _, eku := range c.UnknownExtKeyUsage {
+		if eku.Equal(oidBGPsecRouterEKU) {
+			return true
+		}
+	}
+	return false
+}
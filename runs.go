@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"cloud.google.com/go/bigquery"
+	"github.com/gidoBOSSftw5731/log"
+)
+
+// updateRun is a row in the runs table, the ledger of every update we've
+// done and what went into it.
+type updateRun struct {
+	ID       string
+	Source   string
+	Started  time.Time
+	Fetched  time.Time
+	Finished time.Time
+	// Generated is when the validator says it made the set, if it said
+	Generated bigquery.NullTimestamp
+	// PayloadHash is the hex sha256 of the raw payload the validator served
+	PayloadHash string
+	// ArchiveKey is where the raw payload was archived, if it was
+	ArchiveKey string
+	ROAs       int
+	ASPAs      int
+	RouterKeys int
+	// Provenance is how many ROA sources the validator told us about
+	Provenance int
+	// Events is how many suspicious changes there were, see findEvents
+	Events int
+	// Added and Removed are how many ROAs changed since the last update
+	Added   int
+	Removed int
+	// Staging is how the ROAs got into the staging table
+	Staging stagingStats
+	// TAs is how many ROAs each trust anchor had, and Outages any of them
+	// that looked like they were down
+	TAs     []taRunCount
+	Outages []taOutage
+	Status  string
+	Error   string
+}
+
+func newUpdateRun(source string) *updateRun {
+	now := time.Now()
+	return &updateRun{
+		ID:      strconv.FormatInt(now.UnixNano(), 10),
+		Source:  source,
+		Started: now,
+	}
+}
+
+// finish records the outcome of the run in the runs table
+func (u *updateRun) finish(ctx context.Context, err error) {
+	u.Finished = time.Now()
+	u.Status = "ok"
+	if err != nil {
+		u.Status = "failed"
+		u.Error = err.Error()
+	}
+
+	table, err := ensureTable(ctx, "runs", updateRun{})
+	if err != nil {
+		log.Errorln("can't make runs table: ", err)
+		return
+	}
+	if err := table.Inserter().Put(ctx, u); err != nil {
+		log.Errorln("can't record run: ", err)
+		return
+	}
+	// anyone on /api/stream wants to know
+	stream.poke()
+}
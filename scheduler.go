@@ -0,0 +1,133 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/gidoBOSSftw5731/log"
+)
+
+// scheduler runs updates on its own when there's no App Engine cron hitting
+// /update, eg. when running as a daemon on our own boxes.
+// It goes through beginUpdate like /update does, so both can be enabled at
+// once without anything being merged twice.
+type scheduler struct {
+	interval time.Duration
+	// jitter is the most we wait past each tick, so a fleet of these doesn't
+	// hammer the validator at the top of the hour
+	jitter time.Duration
+	// catchUp runs an update straight away if one was missed (downtime, a
+	// slow update) instead of waiting for the next tick
+	catchUp bool
+	rand    *rand.Rand
+}
+
+// schedulerFromEnv reads the scheduler config from the environment.
+// It returns nil if UPDATE_INTERVAL isn't set, the default is to leave
+// scheduling to cron.yaml.
+//
+//	UPDATE_INTERVAL  time between updates, eg. "60m"
+//	UPDATE_JITTER    max random delay added to each update, eg. "5m"
+//	UPDATE_CATCHUP   "true" to run missed updates immediately
+func schedulerFromEnv() (*scheduler, error) {
+	interval := os.Getenv("UPDATE_INTERVAL")
+	if interval == "" {
+		return nil, nil
+	}
+
+	s := &scheduler{rand: rand.New(rand.NewSource(time.Now().UnixNano()))}
+
+	var err error
+	s.interval, err = time.ParseDuration(interval)
+	if err != nil {
+		return nil, fmt.Errorf("bad UPDATE_INTERVAL: %v", err)
+	}
+	if s.interval <= 0 {
+		return nil, fmt.Errorf("UPDATE_INTERVAL must be positive, got %v", s.interval)
+	}
+
+	if j := os.Getenv("UPDATE_JITTER"); j != "" {
+		s.jitter, err = time.ParseDuration(j)
+		if err != nil {
+			return nil, fmt.Errorf("bad UPDATE_JITTER: %v", err)
+		}
+		if s.jitter < 0 {
+			return nil, fmt.Errorf("UPDATE_JITTER can't be negative, got %v", s.jitter)
+		}
+	}
+
+	if c := os.Getenv("UPDATE_CATCHUP"); c != "" {
+		s.catchUp, err = strconv.ParseBool(c)
+		if err != nil {
+			return nil, fmt.Errorf("bad UPDATE_CATCHUP: %v", err)
+		}
+	}
+
+	if s.interval < recentWindow {
+		log.Errorf("UPDATE_INTERVAL %v is shorter than %v, some updates will be skipped as already done",
+			s.interval, recentWindow)
+	}
+
+	return s, nil
+}
+
+// run schedules updates until ctx is cancelled.
+func (s *scheduler) run(ctx context.Context) {
+	log.Debugf("scheduler started, interval %v jitter %v catchup %v",
+		s.interval, s.jitter, s.catchUp)
+
+	// line up with the last update, no matter who did it
+	next := time.Now().Add(s.interval)
+	if last, err := lastUpdate(ctx); err == nil {
+		next = last.Add(s.interval)
+	} else {
+		log.Errorln("scheduler can't get last update time: ", err)
+	}
+
+	for {
+		if now := time.Now(); next.Before(now) {
+			if s.catchUp {
+				next = now
+			} else {
+				// forget the ones we missed and wait for the next tick
+				for next.Before(now) {
+					next = next.Add(s.interval)
+				}
+			}
+		}
+
+		var jitter time.Duration
+		if s.jitter > 0 {
+			jitter = time.Duration(s.rand.Int63n(int64(s.jitter)))
+		}
+
+		t := time.NewTimer(time.Until(next) + jitter)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return
+		case <-t.C:
+		}
+
+		s.runOnce(ctx)
+		next = next.Add(s.interval)
+	}
+}
+
+// runOnce does a single update, with the same checks /update does.
+func (s *scheduler) runOnce(ctx context.Context) {
+	release, err := beginUpdate(ctx)
+	if err != nil {
+		log.Debugln("scheduled update skipped: ", err)
+		return
+	}
+	defer release()
+
+	if err := updateROAs(ctx); err != nil {
+		log.Errorln("scheduled update failed: ", err)
+	}
+}
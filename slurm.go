@@ -0,0 +1,377 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"sync"
+	"time"
+
+	"cloud.google.com/go/bigquery"
+	pb "github.com/gidoBOSSftw5731/Historical-ROA/proto"
+	"github.com/gidoBOSSftw5731/log"
+	"google.golang.org/api/iterator"
+)
+
+// slurmFile is an RFC 8416 SLURM file: local filters and assertions on top
+// of the RPKI
+type slurmFile struct {
+	Version                 int `json:"slurmVersion"`
+	ValidationOutputFilters struct {
+		PrefixFilters []slurmPrefix `json:"prefixFilters"`
+		BgpsecFilters []slurmBGPsec `json:"bgpsecFilters"`
+	} `json:"validationOutputFilters"`
+	LocallyAddedAssertions struct {
+		PrefixAssertions []slurmPrefix `json:"prefixAssertions"`
+		BgpsecAssertions []slurmBGPsec `json:"bgpsecAssertions"`
+	} `json:"locallyAddedAssertions"`
+
+	// assertions as index entries, made by parse
+	asserted []*roaEntry
+}
+
+// slurmPrefix is a prefix filter or assertion. Filters need a prefix, an ASN
+// or both, assertions need both.
+type slurmPrefix struct {
+	Prefix          string  `json:"prefix,omitempty"`
+	ASN             *uint32 `json:"asn,omitempty"`
+	MaxPrefixLength *int    `json:"maxPrefixLength,omitempty"`
+	Comment         string  `json:"comment,omitempty"`
+
+	prefix netip.Prefix
+}
+
+// slurmBGPsec is a BGPsec filter or assertion
+type slurmBGPsec struct {
+	ASN             *uint32 `json:"asn,omitempty"`
+	SKI             string  `json:"SKI,omitempty"`
+	RouterPublicKey string  `json:"routerPublicKey,omitempty"`
+	Comment         string  `json:"comment,omitempty"`
+}
+
+// slurmTA is the TA asserted ROAs are shown with
+const slurmTA = "slurm"
+
+// parseSLURM reads and checks a SLURM file
+func parseSLURM(b []byte) (*slurmFile, error) {
+	var s slurmFile
+	if err := json.Unmarshal(b, &s); err != nil {
+		return nil, err
+	}
+	if s.Version != 1 {
+		return nil, fmt.Errorf("unsupported slurmVersion %v", s.Version)
+	}
+
+	parsePrefix := func(p *slurmPrefix) error {
+		if p.Prefix == "" {
+			return nil
+		}
+		var err error
+		p.prefix, err = netip.ParsePrefix(p.Prefix)
+		if err != nil {
+			return err
+		}
+		if p.prefix.Masked() != p.prefix {
+			return fmt.Errorf("%v has host bits set", p.Prefix)
+		}
+		return nil
+	}
+
+	filters := s.ValidationOutputFilters.PrefixFilters
+	for i := range filters {
+		if err := parsePrefix(&filters[i]); err != nil {
+			return nil, fmt.Errorf("prefix filter %v: %v", i, err)
+		}
+		if filters[i].Prefix == "" && filters[i].ASN == nil {
+			return nil, fmt.Errorf("prefix filter %v has no prefix or asn", i)
+		}
+	}
+	for i, f := range s.ValidationOutputFilters.BgpsecFilters {
+		if f.SKI == "" && f.ASN == nil {
+			return nil, fmt.Errorf("bgpsec filter %v has no SKI or asn", i)
+		}
+		if f.SKI != "" {
+			if _, err := normaliseSKI(f.SKI); err != nil {
+				return nil, fmt.Errorf("bgpsec filter %v: %v", i, err)
+			}
+		}
+	}
+
+	assertions := s.LocallyAddedAssertions.PrefixAssertions
+	for i := range assertions {
+		a := &assertions[i]
+		if err := parsePrefix(a); err != nil {
+			return nil, fmt.Errorf("prefix assertion %v: %v", i, err)
+		}
+		if a.Prefix == "" || a.ASN == nil {
+			return nil, fmt.Errorf("prefix assertion %v needs a prefix and asn", i)
+		}
+		maxLen := a.prefix.Bits()
+		if a.MaxPrefixLength != nil {
+			maxLen = *a.MaxPrefixLength
+		}
+		if maxLen < a.prefix.Bits() || maxLen > a.prefix.Addr().BitLen() {
+			return nil, fmt.Errorf("prefix assertion %v has a bad maxPrefixLength", i)
+		}
+		s.asserted = append(s.asserted, &roaEntry{
+			ASN:    *a.ASN,
+			Prefix: a.prefix,
+			MaxLen: maxLen,
+			TA:     slurmTA,
+		})
+	}
+	for i, a := range s.LocallyAddedAssertions.BgpsecAssertions {
+		if a.ASN == nil {
+			return nil, fmt.Errorf("bgpsec assertion %v has no asn", i)
+		}
+		if _, err := normaliseRouterKey(inputRouterKey{
+			Asn: fmt.Sprint(*a.ASN), SKI: a.SKI, RouterPublicKey: a.RouterPublicKey,
+		}); err != nil {
+			return nil, fmt.Errorf("bgpsec assertion %v: %v", i, err)
+		}
+	}
+
+	return &s, nil
+}
+
+// filtered says whether a prefix filter drops e. Asserted ROAs aren't
+// filtered (RFC 8416 section 4.2).
+func (s *slurmFile) filtered(e *roaEntry) bool {
+	for _, f := range s.ValidationOutputFilters.PrefixFilters {
+		if f.ASN != nil && *f.ASN != e.ASN {
+			continue
+		}
+		if f.prefix.IsValid() && (f.prefix.Bits() > e.Prefix.Bits() || !f.prefix.Contains(e.Prefix.Addr())) {
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+// filteredKey says whether a BGPsec filter drops k
+func (s *slurmFile) filteredKey(k routerKeyParam) bool {
+	for _, f := range s.ValidationOutputFilters.BgpsecFilters {
+		if f.ASN != nil && int64(*f.ASN) != k.Asnum {
+			continue
+		}
+		if f.SKI != "" {
+			if ski, _ := normaliseSKI(f.SKI); ski != k.SKI {
+				continue
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// assertedKeys is the BGPsec assertions as router keys, with TA slurmTA
+func (s *slurmFile) assertedKeys() []routerKeyParam {
+	var keys []routerKeyParam
+	for _, a := range s.LocallyAddedAssertions.BgpsecAssertions {
+		// checked by parseSLURM
+		k, _ := normaliseRouterKey(inputRouterKey{
+			Asn: fmt.Sprint(*a.ASN), SKI: a.SKI, RouterPublicKey: a.RouterPublicKey, Ta: slurmTA,
+		})
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// slurmRow is a row of the slurm table, one uploaded version. Body is the
+// file as uploaded.
+type slurmRow struct {
+	ID            string
+	Name          string
+	Uploaded      time.Time
+	EffectiveFrom time.Time
+	// EffectiveTo is NULL for no end
+	EffectiveTo bigquery.NullTimestamp
+	Body        string
+}
+
+type slurmVersion struct {
+	slurmRow
+	file *slurmFile
+}
+
+// in says whether the version was in effect at t
+func (v slurmVersion) in(t time.Time) bool {
+	return !t.Before(v.EffectiveFrom) && (!v.EffectiveTo.Valid || t.Before(v.EffectiveTo.Timestamp))
+}
+
+// slurmCache is every SLURM version, oldest upload first. There aren't many
+// so they're kept in memory and reloaded now and then, or after an upload.
+var slurmCache struct {
+	sync.Mutex
+	versions []slurmVersion
+	loaded   time.Time
+}
+
+const slurmCacheFor = 10 * time.Minute
+
+func slurmVersions(ctx context.Context) ([]slurmVersion, error) {
+	slurmCache.Lock()
+	defer slurmCache.Unlock()
+	if time.Since(slurmCache.loaded) < slurmCacheFor {
+		return slurmCache.versions, nil
+	}
+
+	if _, err := ensureTable(ctx, "slurm", slurmRow{}); err != nil {
+		return nil, err
+	}
+	it, err := runQuery(ctx, client.Query(`SELECT * FROM historical.slurm ORDER BY Uploaded`))
+	if err != nil {
+		return nil, err
+	}
+	var versions []slurmVersion
+	for {
+		var row slurmRow
+		err := it.Next(&row)
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		f, err := parseSLURM([]byte(row.Body))
+		if err != nil {
+			log.Errorf("stored SLURM %v doesn't parse: %v", row.ID, err)
+			continue
+		}
+		versions = append(versions, slurmVersion{row, f})
+	}
+
+	slurmCache.versions, slurmCache.loaded = versions, time.Now()
+	return versions, nil
+}
+
+// slurmAt is the SLURM in effect at t, the latest upload wins if more than
+// one was. It's nil if there wasn't one.
+func slurmAt(ctx context.Context, t time.Time) (*slurmFile, error) {
+	versions, err := slurmVersions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := len(versions) - 1; i >= 0; i-- {
+		if versions[i].in(t) {
+			return versions[i].file, nil
+		}
+	}
+	return nil, nil
+}
+
+// slurmUpload stores a new SLURM version.
+//
+//	POST /api/slurm/upload?name=...&from=...[&to=...] with the file as the body
+func slurmUpload(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		ErrorHandler(w, r, 405, "POST a SLURM file", nil)
+		return
+	}
+	body, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, 10<<20))
+	if err != nil {
+		ErrorHandler(w, r, 400, "Can't read SLURM", err)
+		return
+	}
+	if _, err := parseSLURM(body); err != nil {
+		ErrorHandler(w, r, 400, "Bad SLURM", err)
+		return
+	}
+
+	now := time.Now()
+	row := slurmRow{
+		ID:       strconv.FormatInt(now.UnixNano(), 10),
+		Name:     r.FormValue("name"),
+		Uploaded: now,
+		Body:     string(body),
+	}
+	row.EffectiveFrom, err = parseTimeParam(r.FormValue("from"))
+	if err != nil {
+		ErrorHandler(w, r, 400, "Bad from time", err)
+		return
+	}
+	if s := r.FormValue("to"); s != "" {
+		to, err := parseTimeParam(s)
+		if err != nil {
+			ErrorHandler(w, r, 400, "Bad to time", err)
+			return
+		}
+		if !to.After(row.EffectiveFrom) {
+			ErrorHandler(w, r, 400, "Bad to time", errors.New("to must be after from"))
+			return
+		}
+		row.EffectiveTo = bigquery.NullTimestamp{Timestamp: to, Valid: true}
+	}
+
+	table, err := ensureTable(r.Context(), "slurm", slurmRow{})
+	if err != nil {
+		ErrorHandler(w, r, 500, "Can't store SLURM", err)
+		return
+	}
+	if err := table.Inserter().Put(r.Context(), &row); err != nil {
+		ErrorHandler(w, r, 500, "Can't store SLURM", err)
+		return
+	}
+
+	slurmCache.Lock()
+	slurmCache.loaded = time.Time{}
+	slurmCache.Unlock()
+
+	f, _ := parseSLURM(body)
+	writeProto(w, slurmVersionResult(slurmVersion{row, f}))
+}
+
+// slurmAPI lists the SLURM versions, or with id returns one as uploaded.
+//
+//	/api/slurm[?id=...]
+func slurmAPI(w http.ResponseWriter, r *http.Request) {
+	versions, err := slurmVersions(r.Context())
+	if err != nil {
+		ErrorHandler(w, r, 500, "Can't load SLURM", err)
+		return
+	}
+
+	if id := r.FormValue("id"); id != "" {
+		for _, v := range versions {
+			if v.ID == id {
+				w.Header().Set("Content-Type", "application/json")
+				fmt.Fprint(w, v.Body)
+				return
+			}
+		}
+		ErrorHandler(w, r, 404, "No such SLURM", nil)
+		return
+	}
+
+	var out pb.SlurmVersions
+	for _, v := range versions {
+		out.Versions = append(out.Versions, slurmVersionResult(v))
+	}
+	writeProto(w, &out)
+}
+
+func slurmVersionResult(v slurmVersion) *pb.SlurmVersion {
+	out := &pb.SlurmVersion{
+		Id:                   v.ID,
+		Name:                 v.Name,
+		Uploaded:             v.Uploaded.Unix(),
+		RFC3339Uploaded:      v.Uploaded.Format(time.RFC3339),
+		Effectivefrom:        v.EffectiveFrom.Unix(),
+		RFC3339Effectivefrom: v.EffectiveFrom.Format(time.RFC3339),
+		Prefixfilters:        int32(len(v.file.ValidationOutputFilters.PrefixFilters)),
+		Bgpsecfilters:        int32(len(v.file.ValidationOutputFilters.BgpsecFilters)),
+		Prefixassertions:     int32(len(v.file.LocallyAddedAssertions.PrefixAssertions)),
+		Bgpsecassertions:     int32(len(v.file.LocallyAddedAssertions.BgpsecAssertions)),
+	}
+	if v.EffectiveTo.Valid {
+		out.Effectiveto = v.EffectiveTo.Timestamp.Unix()
+		out.RFC3339Effectiveto = v.EffectiveTo.Timestamp.Format(time.RFC3339)
+	}
+	return out
+}
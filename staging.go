@@ -0,0 +1,390 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"cloud.google.com/go/bigquery"
+	"github.com/gidoBOSSftw5731/log"
+	"google.golang.org/api/googleapi"
+)
+
+// stagingChunk is how many rows go in each insert into the staging table
+const stagingChunk = 950
+
+// how hard a batch is retried before the whole update is given up on
+const (
+	stagingAttempts = 5
+	stagingBackoff  = time.Second
+)
+
+// stagingStats is the accounting for one fill of a staging table
+type stagingStats struct {
+	Batches       int
+	Rows          int
+	Retries       int
+	FailedBatches int
+	// Rejected is how many VRPs failed validation, Rejects says why
+	Rejected int
+	Rejects  []rejectCount
+}
+
+// stagingWriter fills a staging table (buf) with ROAs as they're decoded,
+// so we never have to hold the whole set at once. Batches are inserted by a
+// pool of workers and retried on transient errors. Every row gets an insert
+// ID so BigQuery can drop the duplicates a retry might make.
+type stagingWriter struct {
+	ctx     context.Context
+	table   *bigquery.Table
+	schema  bigquery.Schema
+	id      string
+	pending []*storedROA
+	queued  int
+
+	// onRow, if set, is called with every ROA that's written, once even if
+	// it has more than one source
+	onRow func(storedROA)
+	// provenance is how many rows had a source
+	provenance int
+
+	batches chan stagingBatch
+	wg      sync.WaitGroup
+
+	mu    sync.Mutex
+	stats stagingStats
+	err   error
+}
+
+type stagingBatch struct {
+	// first is the row number of rows[0], for the insert IDs
+	first int
+	rows  []*storedROA
+}
+
+// newStagingWriter (re)makes the staging table bufName, empty. id must be
+// unique to this fill, eg. the run ID.
+func newStagingWriter(ctx context.Context, bufName, id string) (*stagingWriter, error) {
+	schema, err := bigquery.InferSchema(storedROA{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to infer schema: %v", err)
+	}
+
+	schema = schema.Relax()
+
+	log.Traceln("making buf table")
+	// make buf table
+
+	table := client.Dataset(dataset).Table(bufName)
+	err = table.Delete(ctx)
+	if err != nil {
+		log.Errorln("Error Deleting buf: ", err)
+		err = nil
+	}
+	err = table.Create(ctx, &bigquery.TableMetadata{Schema: schema})
+	if err != nil {
+		return nil, fmt.Errorf("error creating buf: %v", err)
+	}
+
+	s := &stagingWriter{
+		ctx:     ctx,
+		table:   table,
+		schema:  schema,
+		id:      id,
+		batches: make(chan stagingBatch),
+	}
+	for i := 0; i < stagingWorkers(); i++ {
+		s.wg.Add(1)
+		go s.worker()
+	}
+	return s, nil
+}
+
+// stagingWorkers is how many batches are inserted at once, from
+// STAGING_WORKERS, default 8
+func stagingWorkers() int {
+	if n, err := strconv.Atoi(os.Getenv("STAGING_WORKERS")); err == nil && n > 0 {
+		return n
+	}
+	return 8
+}
+
+// Write queues roas for the staging table, handing a batch to the workers
+// whenever a chunk fills up. ROAs that don't pass normaliseROA are counted
+// and dropped. Once a batch has failed it returns that error.
+func (s *stagingWriter) Write(roas []inputROA) error {
+	for _, i := range roas {
+		s.write(i, true)
+	}
+	return s.failed()
+}
+
+// AddSource stages another source for a ROA that's already been written,
+// without counting the ROA again
+func (s *stagingWriter) AddSource(i inputROA) error {
+	s.write(i, false)
+	return s.failed()
+}
+
+// write queues the rows for i, first is false if it's been written before
+func (s *stagingWriter) write(i inputROA, first bool) {
+	stored, err := normaliseROA(i)
+	if err != nil {
+		if first {
+			s.reject(err.(rejectError))
+		}
+		return
+	}
+
+	if first && s.onRow != nil {
+		s.onRow(stored)
+	}
+
+	// a row per source, the merge takes the distinct ROAs
+	rows := []storedROA{stored}
+	if len(i.Source) > 0 {
+		rows = rows[:0]
+		for _, src := range i.Source {
+			row := stored.withSource(src)
+			if row.URI != "" || row.SKI != "" {
+				s.provenance++
+			}
+			rows = append(rows, row)
+		}
+	}
+
+	for n := range rows {
+		s.pending = append(s.pending, &rows[n])
+		if len(s.pending) >= stagingChunk {
+			s.submit()
+		}
+	}
+}
+
+// Close waits for every batch to go in, then checks the staging table has
+// exactly as many rows as were written. If anything is off the staging table
+// must not be merged.
+func (s *stagingWriter) Close() (stagingStats, error) {
+	s.submit()
+	close(s.batches)
+	s.wg.Wait()
+
+	if err := s.failed(); err != nil {
+		return s.stats, err
+	}
+	if s.stats.Rejected > 0 {
+		log.Errorf("rejected %v VRPs: %+v", s.stats.Rejected, s.stats.Rejects)
+	}
+
+	q := client.Query(fmt.Sprintf("SELECT COUNT(*) FROM %v.%v", dataset, s.table.TableID))
+	it, err := runQuery(s.ctx, q)
+	if err != nil {
+		return s.stats, fmt.Errorf("can't count staged rows: %v", err)
+	}
+	var row []bigquery.Value
+	if err := it.Next(&row); err != nil {
+		return s.stats, fmt.Errorf("can't count staged rows: %v", err)
+	}
+	// insert IDs only dedupe retries on a best effort basis, so there can be
+	// more (the merge takes distinct rows), but fewer means some went missing
+	if n := row[0].(int64); n < int64(s.stats.Rows) {
+		return s.stats, fmt.Errorf("staged %v rows but %v has %v", s.stats.Rows, s.table.TableID, n)
+	}
+
+	return s.stats, nil
+}
+
+// reject counts a VRP that failed validation
+func (s *stagingWriter) reject(e rejectError) {
+	log.Traceln("rejected ", e)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stats.Rejected++
+	for n := range s.stats.Rejects {
+		if s.stats.Rejects[n].Reason == e.reason {
+			s.stats.Rejects[n].Count++
+			return
+		}
+	}
+	s.stats.Rejects = append(s.stats.Rejects, rejectCount{
+		Reason:  e.reason,
+		Count:   1,
+		Example: fmt.Sprintf("%+v", e.roa),
+	})
+}
+
+func (s *stagingWriter) failed() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.err
+}
+
+func (s *stagingWriter) submit() {
+	if len(s.pending) == 0 {
+		return
+	}
+	b := stagingBatch{first: s.queued, rows: s.pending}
+	s.queued += len(s.pending)
+	s.pending = make([]*storedROA, 0, stagingChunk)
+	s.batches <- b
+}
+
+func (s *stagingWriter) worker() {
+	defer s.wg.Done()
+	for b := range s.batches {
+		// no point carrying on once the update is doomed
+		if s.failed() != nil {
+			continue
+		}
+
+		retries, err := s.put(b)
+
+		s.mu.Lock()
+		s.stats.Batches++
+		s.stats.Retries += retries
+		if err != nil {
+			s.stats.FailedBatches++
+			if s.err == nil {
+				s.err = fmt.Errorf("error putting rows %v-%v: %v", b.first, b.first+len(b.rows)-1, err)
+			}
+		} else {
+			s.stats.Rows += len(b.rows)
+		}
+		s.mu.Unlock()
+	}
+}
+
+// put inserts a batch, retrying transient errors with backoff. It returns
+// how many retries it took.
+func (s *stagingWriter) put(b stagingBatch) (int, error) {
+	savers := make([]*bigquery.StructSaver, len(b.rows))
+	for n, r := range b.rows {
+		savers[n] = &bigquery.StructSaver{
+			Schema:   s.schema,
+			InsertID: fmt.Sprintf("%v-%v", s.id, b.first+n),
+			Struct:   r,
+		}
+	}
+
+	backoff := stagingBackoff
+	for attempt := 1; ; attempt++ {
+		err := s.table.Inserter().Put(s.ctx, savers)
+		if err == nil || attempt == stagingAttempts || !transientErr(err) {
+			return attempt - 1, err
+		}
+
+		log.Debugf("retrying batch at %v after %v: %v", b.first, backoff, err)
+		select {
+		case <-s.ctx.Done():
+			return attempt - 1, s.ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// transientErr says whether an insert error is worth retrying
+func transientErr(err error) bool {
+	switch e := err.(type) {
+	case *googleapi.Error:
+		switch e.Code {
+		case http.StatusTooManyRequests, http.StatusInternalServerError,
+			http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			return true
+		}
+		return false
+	case bigquery.PutMultiError:
+		// rows that are actually bad won't get better
+		for _, row := range e {
+			for _, re := range row.Errors {
+				be, ok := re.(*bigquery.Error)
+				if !ok {
+					return false
+				}
+				switch be.Reason {
+				case "backendError", "timeout", "stopped", "internalError", "rateLimitExceeded":
+				default:
+					return false
+				}
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// addTimeSQL is SQL for the times array with @fetched added, newest first. A
+// backfill can be older than what's there, so it goes in order rather than
+// on the front.
+func addTimeSQL(times string) string {
+	return `ARRAY(SELECT t FROM UNNEST(ARRAY_CONCAT([@fetched], ` + times + `)) t
+		ORDER BY t DESC)`
+}
+
+// addGeneratedSQL is SQL for the generated times lined up with times, with
+// @generated added where addTimeSQL puts @fetched
+func addGeneratedSQL(times, generated string) string {
+	return `ARRAY(SELECT g FROM UNNEST(ARRAY_CONCAT(
+			[STRUCT(@fetched AS t, @generated AS g)],
+			ARRAY(SELECT AS STRUCT t, (` + generated + `)[OFFSET(o)] AS g
+				FROM UNNEST(` + times + `) t WITH OFFSET o))) WITH OFFSET o
+		ORDER BY t DESC, o)`
+}
+
+// mergeStaged adds everything in the staging table bufName to roas_arr as
+// fetched at fetched from a set the validator generated at generated.
+func mergeStaged(ctx context.Context, bufName string, fetched, generated time.Time) error {
+	// older tables won't have everything in roaRow yet
+	if _, err := ensureTable(ctx, "roas_arr", roaRow{}); err != nil {
+		return fmt.Errorf("error updating roas_arr: %v", err)
+	}
+
+	// now make one plus one equal 2
+	// historical-roas.historical.roas_arr
+	// rows from before generatedtimes existed get a copy of inserttimes so
+	// the two arrays stay lined up
+	query := client.Query(`MERGE historical.roas_arr arr
+	USING (SELECT DISTINCT Asn, AsnNum, Prefix, MaxLength, Ta, Subnet, Family, StartAddr, EndAddr
+		FROM historical.` + bufName + `) b
+	ON 	b.Asn = arr.asn AND arr.maxlen = b.MaxLength
+	AND b.Prefix = arr.prefix AND arr.ta = b.Ta
+	AND b.Subnet = arr.mask
+	WHEN MATCHED THEN
+ 		UPDATE SET inserttimes = ` + addTimeSQL("arr.inserttimes") + `,
+		asnum = b.AsnNum, family = b.Family,
+		startaddr = b.StartAddr, endaddr = b.EndAddr,
+		generatedtimes = ` + addGeneratedSQL("arr.inserttimes",
+		`IF(ARRAY_LENGTH(arr.generatedtimes) = ARRAY_LENGTH(arr.inserttimes),
+				arr.generatedtimes, arr.inserttimes)`) + `
+	WHEN NOT MATCHED BY TARGET THEN
+		INSERT (asn, asnum, maxlen, prefix, ta, mask, family, startaddr, endaddr,
+			inserttimes, generatedtimes)
+		VALUES (b.Asn, b.AsnNum, b.MaxLength, b.Prefix, b.Ta, b.Subnet, b.Family, b.StartAddr, b.EndAddr,
+			[@fetched], [@generated])`)
+	query.Parameters = []bigquery.QueryParameter{
+		{Name: "fetched", Value: fetched},
+		{Name: "generated", Value: generated},
+	}
+	_, err := runQuery(ctx, query)
+	return err
+}
+
+// mergeExtras merges the ASPAs and router keys from an update, counting them
+// on the run
+func mergeExtras(ctx context.Context, run *updateRun, doc inputROAArr, fetched, generated time.Time) error {
+	var err error
+	run.ASPAs, err = mergeASPAs(ctx, doc.ASPAs, fetched, generated)
+	if err != nil {
+		return fmt.Errorf("error merging ASPAs: %v", err)
+	}
+	run.RouterKeys, err = mergeRouterKeys(ctx, doc.RouterKeys, fetched, generated)
+	if err != nil {
+		return fmt.Errorf("error merging router keys: %v", err)
+	}
+	return nil
+}
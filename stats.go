@@ -0,0 +1,154 @@
+package main
+
+import (
+	"net/http"
+	"sort"
+	"time"
+
+	pb "github.com/gidoBOSSftw5731/Historical-ROA/proto"
+)
+
+// timeSpans turns the times a ROA was seen into spans of consecutive runs,
+// like the index's. Times that aren't in runs are skipped, they're from an
+// update newer than the index.
+func timeSpans(times, runs []time.Time) [][2]int32 {
+	seen := make(map[int64]bool, len(times))
+	for _, t := range times {
+		seen[t.UnixNano()] = true
+	}
+	var spans [][2]int32
+	for i, t := range runs {
+		if !seen[t.UnixNano()] {
+			continue
+		}
+		k := int32(i)
+		if n := len(spans); n > 0 && spans[n-1][1] == k-1 {
+			spans[n-1][1] = k
+			continue
+		}
+		spans = append(spans, [2]int32{k, k})
+	}
+	return spans
+}
+
+// lifespan summarises spans of runs. A ROA that's still there counts as
+// present up to now, or up to when the next update was due if that's
+// passed without one.
+func lifespan(spans [][2]int32, runs []time.Time, now time.Time) *pb.Lifespan {
+	if len(spans) == 0 {
+		return nil
+	}
+	first, last := runs[spans[0][0]], runs[spans[len(spans)-1][1]]
+	out := &pb.Lifespan{
+		Firstseen:        first.Unix(),
+		RFC3339Firstseen: first.Format(time.RFC3339),
+		Lastseen:         last.Unix(),
+		RFC3339Lastseen:  last.Format(time.RFC3339),
+		Gaps:             int32(len(spans) - 1),
+		Current:          int(spans[len(spans)-1][1]) == len(runs)-1,
+	}
+	for i, s := range spans {
+		// gone is the first run it was missing from
+		gone := now
+		if due := runs[len(runs)-1].Add(updateInterval); gone.After(due) {
+			gone = due
+		}
+		if int(s[1])+1 < len(runs) {
+			gone = runs[s[1]+1]
+		}
+		out.Present += int64(gone.Sub(runs[s[0]]) / time.Second)
+		if i+1 < len(spans) {
+			if gap := int64(runs[spans[i+1][0]].Sub(gone) / time.Second); gap > out.Longestgap {
+				out.Longestgap = gap
+			}
+		}
+	}
+	return out
+}
+
+// day is the start of t's day in UTC
+func day(t time.Time) time.Time {
+	return t.UTC().Truncate(24 * time.Hour)
+}
+
+// churn is how many ROAs each TA had added and removed each day between from
+// and to that had an update, with gaps bridged over
+func (idx *roaIndex) churn(keep func(*roaEntry) bool, gaps map[string][][2]int32, from, to time.Time) *pb.Churn {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	// the days in range with updates, and which of them each run is on
+	var days []time.Time
+	dayOf := make([]int, len(idx.runs))
+	for i, t := range idx.runs {
+		dayOf[i] = -1
+		if t.Before(from) || t.After(to) {
+			continue
+		}
+		if d := day(t); len(days) == 0 || !days[len(days)-1].Equal(d) {
+			days = append(days, d)
+		}
+		dayOf[i] = len(days) - 1
+	}
+
+	series := make(map[string]*pb.ChurnSeries)
+	for _, e := range idx.byKey {
+		if keep != nil && !keep(e) {
+			continue
+		}
+		for _, c := range e.changes(gaps, len(idx.runs)) {
+			d := dayOf[c.run]
+			if d == -1 {
+				continue
+			}
+			s, ok := series[e.TA]
+			if !ok {
+				s = &pb.ChurnSeries{Ta: e.TA}
+				for _, t := range days {
+					s.Days = append(s.Days, &pb.ChurnCount{
+						Unixtime:    t.Unix(),
+						RFC3339Time: t.Format(time.RFC3339),
+					})
+				}
+				series[e.TA] = s
+			}
+			if c.added {
+				s.Added++
+				s.Days[d].Added++
+			} else {
+				s.Removed++
+				s.Days[d].Removed++
+			}
+		}
+	}
+
+	var out pb.Churn
+	for _, s := range series {
+		out.Tas = append(out.Tas, s)
+	}
+	sort.Slice(out.Tas, func(i, j int) bool { return out.Tas[i].Ta < out.Tas[j].Ta })
+	return &out
+}
+
+// churnAPI is how many ROAs each TA added and removed per day (UTC) between
+// from and to (both optional). TA outages short enough to bridge are left
+// out unless bridge=false, like the feeds, or they'd swamp everything else.
+//
+//	/api/stats/churn[?ta=arin,ripe][&from=...][&to=...][&bridge=false]
+func churnAPI(w http.ResponseWriter, r *http.Request) {
+	idx := indexOr503(w, r)
+	if idx == nil {
+		return
+	}
+
+	from, to, ok := timeRange(w, r)
+	if !ok {
+		return
+	}
+	var gaps map[string][][2]int32
+	if r.FormValue("bridge") != "false" {
+		gaps, _ = idx.bridgeable()
+	}
+
+	writeProto(w, idx.churn(taFilter(parseTAs(r.FormValue("ta"))), gaps, from, to))
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLifespan(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	var runs []time.Time
+	for i := 0; i < 5; i++ {
+		runs = append(runs, start.Add(time.Duration(i)*updateInterval))
+	}
+
+	// in runs 0-1 and 3-4, and updates stopped days ago
+	now := runs[4].Add(72 * time.Hour)
+	l := lifespan([][2]int32{{0, 1}, {3, 4}}, runs, now)
+	if want := int64(4 * updateInterval / time.Second); l.Present != want {
+		t.Errorf("present %v, want %v (up to when the next update was due)", l.Present, want)
+	}
+	if !l.Current || l.Gaps != 1 || l.Longestgap != int64(updateInterval/time.Second) {
+		t.Errorf("got %+v", l)
+	}
+
+	// just before the next update's due it's up to now
+	now = runs[4].Add(updateInterval / 2)
+	l = lifespan([][2]int32{{4, 4}}, runs, now)
+	if want := int64(updateInterval / 2 / time.Second); l.Present != want {
+		t.Errorf("new ROA present %v, want %v", l.Present, want)
+	}
+
+	// gone before the latest update
+	l = lifespan([][2]int32{{1, 2}}, runs, now)
+	if want := int64(2 * updateInterval / time.Second); l.Present != want || l.Current {
+		t.Errorf("removed ROA got %+v, want present %v", l, want)
+	}
+}
@@ -0,0 +1,380 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"cloud.google.com/go/bigquery"
+	pb "github.com/gidoBOSSftw5731/Historical-ROA/proto"
+	"github.com/gidoBOSSftw5731/log"
+	"google.golang.org/api/iterator"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+const (
+	// streamPoll is how often the stream checks the run ledger for updates,
+	// for ones done by another instance. Our own updates poke it straight
+	// away.
+	streamPoll = time.Minute
+	// streamReplay is the most updates a resumed stream is sent, one that's
+	// missed more than that is reset instead. It's also how many the hub
+	// sends at a time.
+	streamReplay = 48
+	// streamKeepalive is how often an idle stream gets a comment, so
+	// proxies don't time it out
+	streamKeepalive = 30 * time.Second
+)
+
+// changeSet is the changes from one update
+type changeSet struct {
+	id      string
+	at      time.Time
+	changes []changeRow
+	entries []*roaEntry
+}
+
+// changeSetsSince is the changes from the first limit good updates fetched
+// after after, oldest first, from the run ledger and the changes table. more
+// is whether there are updates after those.
+func changeSetsSince(ctx context.Context, after time.Time, limit int) (sets []*changeSet, more bool, err error) {
+	query := client.Query(`SELECT ID, Fetched FROM historical.runs
+	WHERE Status = "ok" AND Fetched > @after
+	ORDER BY Fetched LIMIT @limit`)
+	query.Parameters = []bigquery.QueryParameter{{Name: "after", Value: after}, {Name: "limit", Value: limit + 1}}
+	it, err := runQuery(ctx, query)
+	if err != nil {
+		return nil, false, err
+	}
+
+	byID := make(map[string]*changeSet)
+	var ids []string
+	for {
+		var row struct {
+			ID      string
+			Fetched time.Time
+		}
+		err := it.Next(&row)
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, false, err
+		}
+		if len(sets) == limit {
+			more = true
+			break
+		}
+		s := &changeSet{id: row.ID, at: row.Fetched}
+		sets = append(sets, s)
+		byID[row.ID] = s
+		ids = append(ids, row.ID)
+	}
+	if len(sets) == 0 {
+		return nil, false, nil
+	}
+
+	if _, err := ensureTable(ctx, "changes", changeRow{}); err != nil {
+		return nil, false, err
+	}
+	query = client.Query(`SELECT * REPLACE (IFNULL(Outage, FALSE) AS Outage)
+	FROM historical.changes WHERE RunID IN UNNEST(@ids)`)
+	query.Parameters = []bigquery.QueryParameter{{Name: "ids", Value: ids}}
+	it, err = runQuery(ctx, query)
+	if err != nil {
+		return nil, false, err
+	}
+	for {
+		var row changeRow
+		err := it.Next(&row)
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, false, err
+		}
+		e, err := row.entry()
+		if err != nil {
+			log.Errorf("change has a bad prefix %q: %v", row.Prefix, err)
+			continue
+		}
+		s := byID[row.RunID]
+		s.changes = append(s.changes, row)
+		s.entries = append(s.entries, e)
+	}
+	return sets, more, nil
+}
+
+// streamHub hands each update's changes to every /api/stream client. It
+// only watches the ledger while someone's listening.
+type streamHub struct {
+	mu   sync.Mutex
+	subs map[chan *changeSet]bool
+	// last is when the newest update sent out was fetched
+	last    time.Time
+	running bool
+	poked   chan struct{}
+}
+
+var stream = &streamHub{
+	subs:  make(map[chan *changeSet]bool),
+	poked: make(chan struct{}, 1),
+}
+
+// poke has the hub check for updates now
+func (h *streamHub) poke() {
+	select {
+	case h.poked <- struct{}{}:
+	default:
+	}
+}
+
+func (h *streamHub) subscribe() chan *changeSet {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	ch := make(chan *changeSet, 16)
+	h.subs[ch] = true
+	if !h.running {
+		h.running = true
+		go h.run()
+	}
+	return ch
+}
+
+func (h *streamHub) unsubscribe(ch chan *changeSet) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.subs[ch] {
+		delete(h.subs, ch)
+		close(ch)
+	}
+}
+
+// latestFetched is when the newest good update was fetched
+func latestFetched(ctx context.Context) (time.Time, error) {
+	it, err := runQuery(ctx, client.Query(`SELECT MAX(Fetched) AS Fetched FROM historical.runs WHERE Status = "ok"`))
+	if err != nil {
+		return time.Time{}, err
+	}
+	var row struct{ Fetched bigquery.NullTimestamp }
+	if err := it.Next(&row); err != nil && err != iterator.Done {
+		return time.Time{}, err
+	}
+	return row.Fetched.Timestamp, nil
+}
+
+// run sends out updates until there's no one left to send them to
+func (h *streamHub) run() {
+	ctx := context.Background()
+	// start from now, anything from while nobody was listening isn't live.
+	// Subscribers catch up on that themselves.
+	last, err := latestFetched(ctx)
+	if err != nil {
+		log.Errorln("stream can't read the run ledger: ", err)
+		last = time.Now()
+	}
+	h.mu.Lock()
+	h.last = last
+	h.mu.Unlock()
+
+	tick := time.NewTicker(streamPoll)
+	defer tick.Stop()
+	for {
+		select {
+		case <-tick.C:
+		case <-h.poked:
+		}
+
+		h.mu.Lock()
+		if len(h.subs) == 0 {
+			h.running = false
+			h.mu.Unlock()
+			return
+		}
+		last := h.last
+		h.mu.Unlock()
+
+		sets, more, err := changeSetsSince(ctx, last, streamReplay)
+		if err != nil {
+			log.Errorln("stream can't load changes: ", err)
+			continue
+		}
+
+		h.mu.Lock()
+		for _, s := range sets {
+			for ch := range h.subs {
+				select {
+				case ch <- s:
+				default:
+					// too slow, it can resume with Last-Event-ID
+					delete(h.subs, ch)
+					close(ch)
+				}
+			}
+			h.last = s.at
+		}
+		h.mu.Unlock()
+		if more {
+			// carry on from there straight away
+			h.poke()
+		}
+	}
+}
+
+// streamFilter is the watchlist-style filter from a stream's parameters,
+// comma separated lists of ASNs, prefixes and TAs
+func streamFilter(r *http.Request) (*watchRow, error) {
+	f := &watchRow{TAs: parseTAs(r.FormValue("ta"))}
+	for _, s := range strings.Split(r.FormValue("asn"), ",") {
+		if strings.TrimSpace(s) == "" {
+			continue
+		}
+		asn, err := parseASN(s)
+		if err != nil {
+			return nil, err
+		}
+		f.ASNs = append(f.ASNs, int64(asn))
+	}
+	for _, s := range strings.Split(r.FormValue("prefix"), ",") {
+		if strings.TrimSpace(s) != "" {
+			f.Prefixes = append(f.Prefixes, s)
+		}
+	}
+	return f, f.parse()
+}
+
+// writeChangeSet sends the part of s that f covers as an SSE event, if
+// there's anything
+func writeChangeSet(w http.ResponseWriter, s *changeSet, f *watchRow) error {
+	out := &pb.Changes{
+		Run:         s.id,
+		Unixtime:    s.at.Unix(),
+		RFC3339Time: s.at.Format(time.RFC3339),
+	}
+	for i, e := range s.entries {
+		if !f.covers(e) {
+			continue
+		}
+		switch c := s.changes[i]; {
+		case c.Outage && c.Added:
+			out.Outageadded = append(out.Outageadded, entryResult(e))
+		case c.Outage:
+			out.Outageremoved = append(out.Outageremoved, entryResult(e))
+		case c.Added:
+			out.Added = append(out.Added, entryResult(e))
+		default:
+			out.Removed = append(out.Removed, entryResult(e))
+		}
+	}
+	if len(out.Added)+len(out.Removed)+len(out.Outageadded)+len(out.Outageremoved) == 0 {
+		return nil
+	}
+
+	data, err := protojson.Marshal(out)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %v\nevent: changes\ndata: %s\n\n", s.id, data)
+	return err
+}
+
+// streamAPI sends the ROAs each update adds and removes as server-sent
+// events, one "changes" event per update with the update's run ID as its ID.
+// asn, prefix and ta filter them like a watchlist, and changes that are
+// probably a TA outage are kept apart like in webhooks. A client resuming
+// with Last-Event-ID (or lastEventId) is sent what it missed first, oldest
+// first. If that's more than streamReplay updates it gets a "reset" event
+// instead, and should reload from /api/snapshot before carrying on.
+//
+//	/api/stream[?asn=AS64496,AS64497][&prefix=192.0.2.0/24][&ta=...]
+func streamAPI(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		ErrorHandler(w, r, 500, "Can't stream", nil)
+		return
+	}
+	f, err := streamFilter(r)
+	if err != nil {
+		ErrorHandler(w, r, 400, "Bad filter", err)
+		return
+	}
+
+	// subscribe first so nothing's missed while catching up
+	ch := stream.subscribe()
+	defer stream.unsubscribe(ch)
+
+	var replay []*changeSet
+	var reset bool
+	lastID := r.Header.Get("Last-Event-ID")
+	if lastID == "" {
+		lastID = r.FormValue("lastEventId")
+	}
+	if lastID != "" {
+		query := client.Query(`SELECT Fetched FROM historical.runs WHERE ID = @id`)
+		query.Parameters = []bigquery.QueryParameter{{Name: "id", Value: lastID}}
+		it, err := runQuery(r.Context(), query)
+		var row struct{ Fetched time.Time }
+		if err == nil {
+			err = it.Next(&row)
+		}
+		if err == iterator.Done {
+			ErrorHandler(w, r, 400, "Unknown Last-Event-ID", nil)
+			return
+		}
+		if err != nil {
+			ErrorHandler(w, r, 500, "Can't read the run ledger", err)
+			return
+		}
+		replay, reset, err = changeSetsSince(r.Context(), row.Fetched, streamReplay)
+		if err != nil {
+			ErrorHandler(w, r, 500, "Can't load changes", err)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	fmt.Fprint(w, "retry: 60000\n\n")
+
+	var sent time.Time
+	if reset {
+		// too much was missed to catch up on, the client has to start again
+		// from a snapshot. The empty id stops it resuming from the old one.
+		fmt.Fprint(w, "id\nevent: reset\ndata: {}\n\n")
+		replay = nil
+	}
+	for _, s := range replay {
+		if err := writeChangeSet(w, s, f); err != nil {
+			return
+		}
+		sent = s.at
+	}
+	flusher.Flush()
+
+	keepalive := time.NewTicker(streamKeepalive)
+	defer keepalive.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-keepalive.C:
+			fmt.Fprint(w, ": keepalive\n\n")
+		case s, ok := <-ch:
+			if !ok {
+				return
+			}
+			if !s.at.After(sent) {
+				continue
+			}
+			if err := writeChangeSet(w, s, f); err != nil {
+				return
+			}
+			sent = s.at
+		}
+		flusher.Flush()
+	}
+}
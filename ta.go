@@ -0,0 +1,153 @@
+package main
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/bigquery"
+	pb "github.com/gidoBOSSftw5731/Historical-ROA/proto"
+)
+
+// parseTAs reads a comma separated list of trust anchors, validators don't
+// agree on case so they're lowercased
+func parseTAs(s string) []string {
+	var tas []string
+	for _, ta := range strings.Split(s, ",") {
+		ta = strings.ToLower(strings.TrimSpace(ta))
+		if ta != "" {
+			tas = append(tas, ta)
+		}
+	}
+	return tas
+}
+
+// taFilter keeps index entries from one of tas, or everything if there
+// aren't any
+func taFilter(tas []string) func(*roaEntry) bool {
+	if len(tas) == 0 {
+		return nil
+	}
+	return func(e *roaEntry) bool {
+		for _, ta := range tas {
+			if strings.EqualFold(e.TA, ta) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// taCondition is a WHERE clause on roas_arr for ROAs from one of tas
+func taCondition(tas []string) (string, bigquery.QueryParameter) {
+	return "LOWER(ta) IN UNNEST(@tas)", bigquery.QueryParameter{Name: "tas", Value: tas}
+}
+
+// taCounts is how many ROAs each TA had in every run, worked out from the
+// spans rather than checking every entry against every run
+func (idx *roaIndex) taCounts() map[string][]int64 {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	counts := make(map[string][]int64)
+	for _, e := range idx.byKey {
+		c, ok := counts[e.TA]
+		if !ok {
+			c = make([]int64, len(idx.runs)+1)
+			counts[e.TA] = c
+		}
+		for _, s := range e.spans {
+			c[s[0]]++
+			c[s[1]+1]--
+		}
+	}
+
+	for ta, c := range counts {
+		for i := 1; i < len(c); i++ {
+			c[i] += c[i-1]
+		}
+		counts[ta] = c[:len(idx.runs)]
+	}
+	return counts
+}
+
+// tasAPI lists every trust anchor we've seen, when, how many ROAs it has and
+// when it looked like it was down. With counts=true it includes the count in
+// every update between from and to (both optional).
+//
+//	/api/tas[?ta=arin,ripe][&counts=true][&from=...][&to=...]
+func tasAPI(w http.ResponseWriter, r *http.Request) {
+	idx := indexOr503(w, r)
+	if idx == nil {
+		return
+	}
+
+	from, to, ok := timeRange(w, r)
+	if !ok {
+		return
+	}
+	withCounts := r.FormValue("counts") == "true"
+	keep := taFilter(parseTAs(r.FormValue("ta")))
+
+	counts := idx.taCounts()
+	idx.mu.RLock()
+	runs := idx.runs
+	idx.mu.RUnlock()
+
+	var out pb.TrustAnchors
+	for ta, c := range counts {
+		if keep != nil && !keep(&roaEntry{TA: ta}) {
+			continue
+		}
+
+		first, last := -1, -1
+		for i, n := range c {
+			if n > 0 {
+				if first == -1 {
+					first = i
+				}
+				last = i
+			}
+		}
+		if first == -1 {
+			continue
+		}
+
+		t := &pb.TrustAnchor{
+			Ta:               ta,
+			Firstseen:        runs[first].Unix(),
+			RFC3339Firstseen: runs[first].Format(time.RFC3339),
+			Lastseen:         runs[last].Unix(),
+			RFC3339Lastseen:  runs[last].Format(time.RFC3339),
+			Roas:             c[len(c)-1],
+		}
+		if withCounts {
+			for i, n := range c {
+				if runs[i].Before(from) || runs[i].After(to) {
+					continue
+				}
+				t.Counts = append(t.Counts, &pb.TACount{
+					Unixtime:    runs[i].Unix(),
+					RFC3339Time: runs[i].Format(time.RFC3339),
+					Roas:        n,
+				})
+			}
+		}
+		for _, o := range findOutages(c) {
+			start, end := runs[o[0]], runs[o[1]]
+			t.Outages = append(t.Outages, &pb.TAOutage{
+				Start:        start.Unix(),
+				RFC3339Start: start.Format(time.RFC3339),
+				End:          end.Unix(),
+				RFC3339End:   end.Format(time.RFC3339),
+				Baseline:     c[o[0]-1],
+				Bridged:      o[1]-o[0] < bridgeMax && int(o[1])+1 < len(c),
+			})
+		}
+		out.Tas = append(out.Tas, t)
+	}
+	sort.Slice(out.Tas, func(i, j int) bool { return out.Tas[i].Ta < out.Tas[j].Ta })
+
+	writeProto(w, &out)
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"cloud.google.com/go/bigquery"
+	"google.golang.org/api/googleapi"
+)
+
+// dataset is where all our tables live
+const dataset = "historical"
+
+// ensureTable returns the table called name, creating it from st's schema if
+// it doesn't exist yet. Columns added to st since the table was made get
+// added to the table too, so structs can grow without manual migrations.
+func ensureTable(ctx context.Context, name string, st interface{}) (*bigquery.Table, error) {
+	schema, err := bigquery.InferSchema(st)
+	if err != nil {
+		return nil, err
+	}
+	schema = schema.Relax()
+
+	table := client.Dataset(dataset).Table(name)
+	meta, err := table.Metadata(ctx)
+	if e, ok := err.(*googleapi.Error); ok && e.Code == http.StatusNotFound {
+		return table, table.Create(ctx, &bigquery.TableMetadata{Schema: schema})
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	have := make(map[string]struct{})
+	for _, f := range meta.Schema {
+		have[strings.ToLower(f.Name)] = struct{}{}
+	}
+	newSchema := meta.Schema
+	for _, f := range schema {
+		if _, ok := have[strings.ToLower(f.Name)]; !ok {
+			newSchema = append(newSchema, f)
+		}
+	}
+	if len(newSchema) == len(meta.Schema) {
+		return table, nil
+	}
+
+	_, err = table.Update(ctx, bigquery.TableMetadataToUpdate{Schema: newSchema}, meta.ETag)
+	return table, err
+}
+
+// runQuery runs q, waits for it to finish and returns its rows
+func runQuery(ctx context.Context, q *bigquery.Query) (*bigquery.RowIterator, error) {
+	job, err := q.Run(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	status, err := job.Wait(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := status.Err(); err != nil {
+		return nil, err
+	}
+
+	return job.Read(ctx)
+}
+
+// ensureClustering clusters table by fields if it isn't already. Only new
+// data is clustered straight away, BigQuery gets around to the rest.
+func ensureClustering(ctx context.Context, table *bigquery.Table, fields []string) error {
+	meta, err := table.Metadata(ctx)
+	if err != nil {
+		return err
+	}
+	if meta.Clustering != nil && strings.EqualFold(
+		strings.Join(meta.Clustering.Fields, ","), strings.Join(fields, ",")) {
+		return nil
+	}
+
+	_, err = table.Update(ctx, bigquery.TableMetadataToUpdate{
+		Clustering: &bigquery.Clustering{Fields: fields},
+	}, meta.ETag)
+	return err
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"net/netip"
+	"unsafe"
+)
+
+// trieNode is a node in a path compressed binary (patricia) trie of
+// prefixes. Every child's prefix is inside its parent's, and which child it
+// is depends on the first bit after the parent's mask.
+type trieNode struct {
+	prefix netip.Prefix
+	// roas are the ROAs for exactly this prefix, glue nodes have none
+	roas  []*roaEntry
+	child [2]*trieNode
+}
+
+// trieNodeSize is roughly what a node costs, for IndexStats
+const trieNodeSize = int64(unsafe.Sizeof(trieNode{}))
+
+// newTrie makes the root for a family, 0.0.0.0/0 or ::/0
+func newTrie(family int) *trieNode {
+	if family == 4 {
+		return &trieNode{prefix: netip.PrefixFrom(netip.IPv4Unspecified(), 0)}
+	}
+	return &trieNode{prefix: netip.PrefixFrom(netip.IPv6Unspecified(), 0)}
+}
+
+// addrBit is bit i of a, counting from the most significant
+func addrBit(a netip.Addr, i int) int {
+	b := a.AsSlice()
+	return int(b[i/8]>>(7-uint(i%8))) & 1
+}
+
+// commonBits is how many leading bits a and b share, up to the shorter mask
+func commonBits(a, b netip.Prefix) int {
+	max := a.Bits()
+	if b.Bits() < max {
+		max = b.Bits()
+	}
+	ab, bb := a.Addr().AsSlice(), b.Addr().AsSlice()
+	for i := 0; i < max; i++ {
+		if ab[i/8] != bb[i/8] {
+			// somewhere in this byte, find the bit
+			for ; i < max; i++ {
+				if addrBit(a.Addr(), i) != addrBit(b.Addr(), i) {
+					return i
+				}
+			}
+			return max
+		}
+		i += 7 - i%8
+	}
+	return max
+}
+
+// insert adds e for the masked prefix p under n, which must contain p.
+// It returns how many nodes it made.
+func (n *trieNode) insert(p netip.Prefix, e *roaEntry) int {
+	for {
+		if n.prefix == p {
+			n.roas = append(n.roas, e)
+			return 0
+		}
+
+		b := addrBit(p.Addr(), n.prefix.Bits())
+		c := n.child[b]
+		if c == nil {
+			n.child[b] = &trieNode{prefix: p, roas: []*roaEntry{e}}
+			return 1
+		}
+
+		common := commonBits(c.prefix, p)
+		switch {
+		case common == c.prefix.Bits():
+			// c contains p, keep going
+			n = c
+			continue
+		case common == p.Bits():
+			// p contains c, p goes in between
+			nn := &trieNode{prefix: p, roas: []*roaEntry{e}}
+			nn.child[addrBit(c.prefix.Addr(), p.Bits())] = c
+			n.child[b] = nn
+			return 1
+		}
+
+		// they split somewhere below n, so they need a glue node there
+		glue := &trieNode{prefix: netip.PrefixFrom(p.Addr(), common).Masked()}
+		glue.child[addrBit(p.Addr(), common)] = &trieNode{prefix: p, roas: []*roaEntry{e}}
+		glue.child[addrBit(c.prefix.Addr(), common)] = c
+		n.child[b] = glue
+		return 2
+	}
+}
+
+// covering calls fn for every node from n down to p whose prefix contains p,
+// least specific first, so the last call is the longest match.
+func (n *trieNode) covering(p netip.Prefix, fn func(*trieNode)) {
+	for n != nil {
+		if n.prefix.Bits() > p.Bits() || !n.prefix.Contains(p.Addr()) {
+			return
+		}
+		fn(n)
+		if n.prefix.Bits() == p.Bits() {
+			return
+		}
+		n = n.child[addrBit(p.Addr(), n.prefix.Bits())]
+	}
+}
+
+// covered calls fn for every node under n whose prefix is inside p
+func (n *trieNode) covered(p netip.Prefix, fn func(*trieNode)) {
+	for n != nil {
+		if n.prefix.Bits() >= p.Bits() {
+			if p.Contains(n.prefix.Addr()) {
+				n.walk(fn)
+			}
+			return
+		}
+		if !n.prefix.Contains(p.Addr()) {
+			return
+		}
+		n = n.child[addrBit(p.Addr(), n.prefix.Bits())]
+	}
+}
+
+// walk calls fn for n and everything under it
+func (n *trieNode) walk(fn func(*trieNode)) {
+	if n == nil {
+		return
+	}
+	fn(n)
+	n.child[0].walk(fn)
+	n.child[1].walk(fn)
+}
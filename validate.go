@@ -0,0 +1,117 @@
+package main
+
+import (
+	"fmt"
+	"net/netip"
+	"strconv"
+	"strings"
+)
+
+// reasons a VRP gets rejected at ingest
+const (
+	rejectPrefix   = "bad prefix"
+	rejectHostBits = "host bits set"
+	rejectMaxLen   = "bad max length"
+	rejectASN      = "bad asn"
+)
+
+// rejectError is why normaliseROA refused a VRP
+type rejectError struct {
+	reason string
+	roa    inputROA
+}
+
+func (e rejectError) Error() string {
+	return fmt.Sprintf("%v: %+v", e.reason, e.roa)
+}
+
+// rejectCount is how many VRPs a run rejected for one reason, with the
+// first one as an example
+type rejectCount struct {
+	Reason  string
+	Count   int
+	Example string
+}
+
+// normaliseROA checks a VRP from a validator and converts it to what we
+// store: the canonical network address (lowercase for IPv6), the mask, a
+// maxLength between the mask and the family's length, and an "AS<n>" ASN.
+func normaliseROA(i inputROA) (storedROA, error) {
+	p, err := netip.ParsePrefix(strings.TrimSpace(i.Prefix))
+	if err != nil {
+		return storedROA{}, rejectError{rejectPrefix, i}
+	}
+	if p.Masked() != p {
+		return storedROA{}, rejectError{rejectHostBits, i}
+	}
+	if i.MaxLength < p.Bits() || i.MaxLength > p.Addr().BitLen() {
+		return storedROA{}, rejectError{rejectMaxLen, i}
+	}
+
+	asn, err := parseASN(i.Asn)
+	if err != nil {
+		return storedROA{}, rejectError{rejectASN, i}
+	}
+
+	start, end := prefixRange(p)
+	return storedROA{
+		Asn:       fmt.Sprintf("AS%d", asn),
+		AsnNum:    asn,
+		Prefix:    p.Addr().String(),
+		MaxLength: i.MaxLength,
+		Ta:        i.Ta,
+		Subnet:    p.Bits(),
+		Family:    prefixFamily(p),
+		StartAddr: start,
+		EndAddr:   end,
+	}, nil
+}
+
+// parseASN reads an ASN as a plain number ("54054"), with an AS prefix in
+// any case ("AS54054", "as54054") or in asdot ("0.54054", "1.10")
+func parseASN(s string) (uint32, error) {
+	s = strings.TrimSpace(s)
+	if len(s) > 2 && strings.EqualFold(s[:2], "AS") {
+		s = s[2:]
+	}
+
+	if dot := strings.IndexByte(s, '.'); dot != -1 {
+		hi, err := strconv.ParseUint(s[:dot], 10, 16)
+		if err != nil {
+			return 0, fmt.Errorf("bad asdot ASN %q", s)
+		}
+		lo, err := strconv.ParseUint(s[dot+1:], 10, 16)
+		if err != nil {
+			return 0, fmt.Errorf("bad asdot ASN %q", s)
+		}
+		return uint32(hi<<16 | lo), nil
+	}
+
+	n, err := strconv.ParseUint(s, 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("bad ASN %q", s)
+	}
+	return uint32(n), nil
+}
+
+// parseASNRange reads a single ASN in any form parseASN takes, or a range of
+// them like "AS64512-AS65534", returning the first and last ASN in it.
+func parseASNRange(s string) (uint32, uint32, error) {
+	if dash := strings.IndexByte(s, '-'); dash != -1 {
+		lo, err := parseASN(s[:dash])
+		if err != nil {
+			return 0, 0, err
+		}
+		hi, err := parseASN(s[dash+1:])
+		if err != nil {
+			return 0, 0, err
+		}
+		if hi < lo {
+			return 0, 0, fmt.Errorf("backwards ASN range %q", s)
+		}
+		return lo, hi, nil
+	}
+
+	asn, err := parseASN(s)
+	return asn, asn, err
+}
@@ -0,0 +1,447 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"cloud.google.com/go/bigquery"
+	pb "github.com/gidoBOSSftw5731/Historical-ROA/proto"
+	"github.com/gidoBOSSftw5731/log"
+	"google.golang.org/api/iterator"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// webhook delivery is tried webhookTries times, waiting webhookBackoff and
+// then twice as long each time
+const (
+	webhookTimeout = 10 * time.Second
+	webhookTries   = 3
+	webhookBackoff = 2 * time.Second
+)
+
+// webhookClient sends webhooks, only to addresses webhookAllowed allows.
+// It's checked as it connects so redirects and DNS changes can't get round
+// it, and there's no proxy since that's what it would check.
+var webhookClient = &http.Client{
+	Transport: &http.Transport{
+		DialContext: (&net.Dialer{
+			Timeout: webhookTimeout,
+			Control: func(network, address string, c syscall.RawConn) error {
+				host, _, err := net.SplitHostPort(address)
+				if err != nil {
+					return err
+				}
+				ip, err := netip.ParseAddr(host)
+				if err != nil {
+					return err
+				}
+				if !webhookAllowed(ip) {
+					return fmt.Errorf("webhooks can't go to %v", ip)
+				}
+				return nil
+			},
+		}).DialContext,
+	},
+}
+
+// webhookBlocked are the special purpose ranges (RFC 6890 and the IANA
+// registries) webhooks can't go to: private, shared, loopback, link-local,
+// documentation, benchmarking, multicast and reserved space, and the
+// translation and tunnel ranges that could reach any of those.
+var webhookBlocked = func() []netip.Prefix {
+	var out []netip.Prefix
+	for _, s := range []string{
+		"0.0.0.0/8", "10.0.0.0/8", "100.64.0.0/10", "127.0.0.0/8",
+		"169.254.0.0/16", "172.16.0.0/12", "192.0.0.0/24", "192.0.2.0/24",
+		"192.88.99.0/24", "192.168.0.0/16", "198.18.0.0/15", "198.51.100.0/24",
+		"203.0.113.0/24", "224.0.0.0/4", "240.0.0.0/4",
+		"::/128", "::1/128", "::ffff:0:0/96", "64:ff9b::/96", "64:ff9b:1::/48",
+		"100::/64", "2001::/23", "2001:db8::/32", "2002::/16", "fc00::/7",
+		"fe80::/10", "ff00::/8",
+	} {
+		out = append(out, netip.MustParsePrefix(s))
+	}
+	return out
+}()
+
+// privateWebhooks is whether WEBHOOK_PRIVATE is true, letting webhooks go
+// to the webhookBlocked ranges, for testing with webhookreceiver
+func privateWebhooks() bool {
+	return os.Getenv("WEBHOOK_PRIVATE") == "true"
+}
+
+// webhookAllowed says whether webhooks can go to ip. Anyone who can make a
+// watchlist could point one at something only we can reach otherwise.
+func webhookAllowed(ip netip.Addr) bool {
+	if privateWebhooks() {
+		return true
+	}
+	ip = ip.Unmap().WithZone("")
+	for _, p := range webhookBlocked {
+		if p.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// watchRow is a row of the watchlists table. They're changed with DML rather
+// than streamed in, so they can be deleted straight away.
+type watchRow struct {
+	ID       string
+	Name     string
+	ASNs     []int64
+	Prefixes []string
+	TAs      []string
+	URL      string
+	Secret   string
+	Created  time.Time
+
+	prefixes []netip.Prefix
+}
+
+// covers says whether a change is in the watchlist's scope: it's for one of
+// the ASNs, or a ROA overlapping one of the prefixes (so covering ones
+// count too), or there are neither, and it's from one of the TAs if there
+// are any.
+func (wl *watchRow) covers(e *roaEntry) bool {
+	if len(wl.TAs) > 0 && !taFilter(wl.TAs)(e) {
+		return false
+	}
+	if len(wl.ASNs) == 0 && len(wl.prefixes) == 0 {
+		return true
+	}
+	for _, asn := range wl.ASNs {
+		if uint32(asn) == e.ASN {
+			return true
+		}
+	}
+	for _, p := range wl.prefixes {
+		if p.Overlaps(e.Prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func (wl *watchRow) parse() error {
+	wl.prefixes = nil
+	for _, s := range wl.Prefixes {
+		p, err := parsePrefixParam(s)
+		if err != nil {
+			return err
+		}
+		wl.prefixes = append(wl.prefixes, p)
+	}
+	return nil
+}
+
+func watchlists(ctx context.Context) ([]*watchRow, error) {
+	if _, err := ensureTable(ctx, "watchlists", watchRow{}); err != nil {
+		return nil, err
+	}
+	it, err := runQuery(ctx, client.Query(`SELECT * FROM historical.watchlists ORDER BY Created`))
+	if err != nil {
+		return nil, err
+	}
+
+	var out []*watchRow
+	for {
+		var row watchRow
+		err := it.Next(&row)
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if err := row.parse(); err != nil {
+			log.Errorf("watchlist %v has a bad prefix: %v", row.ID, err)
+			continue
+		}
+		out = append(out, &row)
+	}
+	return out, nil
+}
+
+// signWebhook makes a webhook's X-Webhook-Signature,
+// hex(HMAC-SHA256(secret, timestamp + "\n" + body))
+func signWebhook(secret []byte, ts string, body []byte) []byte {
+	mac := hmac.New(sha256.New, secret)
+	fmt.Fprintf(mac, "%s\n", ts)
+	mac.Write(body)
+	return mac.Sum(nil)
+}
+
+// sendWebhook POSTs body to the watchlist's URL, signed with its secret
+func sendWebhook(ctx context.Context, wl *watchRow, body []byte) error {
+	var err error
+	wait := webhookBackoff
+	for try := 0; try < webhookTries; try++ {
+		if try > 0 {
+			time.Sleep(wait)
+			wait *= 2
+		}
+		if err = postWebhook(ctx, wl, body); err == nil {
+			return nil
+		}
+	}
+	return err
+}
+
+func postWebhook(ctx context.Context, wl *watchRow, body []byte) error {
+	ctx, cancel := context.WithTimeout(ctx, webhookTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, wl.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	ts := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Webhook-Id", wl.ID)
+	req.Header.Set("X-Webhook-Timestamp", ts)
+	req.Header.Set("X-Webhook-Signature", hex.EncodeToString(signWebhook([]byte(wl.Secret), ts, body)))
+
+	resp, err := webhookClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	ioutil.ReadAll(resp.Body)
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("webhook got %v", resp.Status)
+	}
+	return nil
+}
+
+// notifyWatchlists sends a webhook to every watchlist the changes from run id
+// fetched at fetched touch, all at once, and waits for them. Slow webhooks
+// shouldn't hold up updates, so it's run in the background.
+func notifyWatchlists(ctx context.Context, id string, fetched time.Time, changes []changeRow) {
+	if len(changes) == 0 {
+		return
+	}
+	lists, err := watchlists(ctx)
+	if err != nil {
+		log.Errorln("can't load watchlists: ", err)
+		return
+	}
+
+	entries := make([]*roaEntry, len(changes))
+	for i, c := range changes {
+		entries[i], err = c.entry()
+		if err != nil {
+			log.Errorf("change has a bad prefix %q: %v", c.Prefix, err)
+		}
+	}
+
+	var wg sync.WaitGroup
+	for _, wl := range lists {
+		n := &pb.WatchNotification{
+			Watchlist:   wl.ID,
+			Name:        wl.Name,
+			Run:         id,
+			Unixtime:    fetched.Unix(),
+			RFC3339Time: fetched.Format(time.RFC3339),
+		}
+		for i, e := range entries {
+			if e == nil || !wl.covers(e) {
+				continue
+			}
+			switch c := changes[i]; {
+			case c.Outage && c.Added:
+				n.Outageadded = append(n.Outageadded, entryResult(e))
+			case c.Outage:
+				n.Outageremoved = append(n.Outageremoved, entryResult(e))
+			case c.Added:
+				n.Added = append(n.Added, entryResult(e))
+			default:
+				n.Removed = append(n.Removed, entryResult(e))
+			}
+		}
+		if len(n.Added)+len(n.Removed)+len(n.Outageadded)+len(n.Outageremoved) == 0 {
+			continue
+		}
+
+		body, err := protojson.Marshal(n)
+		if err != nil {
+			log.Errorln("can't marshal webhook: ", err)
+			continue
+		}
+		wg.Add(1)
+		go func(wl *watchRow) {
+			defer wg.Done()
+			if err := sendWebhook(ctx, wl, body); err != nil {
+				log.Errorf("webhook for watchlist %v to %v failed: %v", wl.ID, wl.URL, err)
+			}
+		}(wl)
+	}
+	wg.Wait()
+}
+
+func watchResult(wl *watchRow) *pb.Watchlist {
+	out := &pb.Watchlist{
+		Id:             wl.ID,
+		Name:           wl.Name,
+		Prefixes:       wl.Prefixes,
+		Tas:            wl.TAs,
+		Url:            wl.URL,
+		Created:        wl.Created.Unix(),
+		RFC3339Created: wl.Created.Format(time.RFC3339),
+	}
+	for _, asn := range wl.ASNs {
+		out.Asns = append(out.Asns, fmt.Sprintf("AS%d", asn))
+	}
+	return out
+}
+
+// newWatchlist checks a watchlist from the API and makes a row for it, with a
+// secret if it didn't come with one
+func newWatchlist(in *pb.Watchlist) (*watchRow, error) {
+	u, err := url.Parse(in.Url)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("bad url %q", in.Url)
+	}
+	// names are checked when they're sent to, this catches the obvious ones
+	ip, err := netip.ParseAddr(u.Hostname())
+	if (err == nil && !webhookAllowed(ip)) || (u.Hostname() == "localhost" && !privateWebhooks()) {
+		return nil, fmt.Errorf("webhooks can't go to %v", u.Hostname())
+	}
+
+	now := time.Now()
+	wl := &watchRow{
+		ID:      strconv.FormatInt(now.UnixNano(), 10),
+		Name:    in.Name,
+		TAs:     parseTAs(strings.Join(in.Tas, ",")),
+		URL:     in.Url,
+		Secret:  in.Secret,
+		Created: now,
+	}
+	for _, s := range in.Asns {
+		asn, err := parseASN(s)
+		if err != nil {
+			return nil, err
+		}
+		wl.ASNs = append(wl.ASNs, int64(asn))
+	}
+	for _, s := range in.Prefixes {
+		p, err := parsePrefixParam(s)
+		if err != nil {
+			return nil, err
+		}
+		wl.Prefixes = append(wl.Prefixes, p.String())
+	}
+	if err := wl.parse(); err != nil {
+		return nil, err
+	}
+
+	if wl.Secret == "" {
+		b := make([]byte, 32)
+		if _, err := rand.Read(b); err != nil {
+			return nil, err
+		}
+		wl.Secret = hex.EncodeToString(b)
+	}
+	return wl, nil
+}
+
+// watchlistAPI manages watchlists. GET lists them, POST makes one from a
+// Watchlist (the secret is only in the reply, and is made up if it isn't
+// given) and DELETE removes one. It needs the same auth as /update since
+// watchlists hold secrets.
+//
+//	GET    /api/watchlists
+//	POST   /api/watchlists {"name": ..., "asns": [...], "prefixes": [...], "tas": [...], "url": ...}
+//	DELETE /api/watchlists?id=...
+func watchlistAPI(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	switch r.Method {
+	case http.MethodGet:
+		lists, err := watchlists(ctx)
+		if err != nil {
+			ErrorHandler(w, r, 500, "Can't load watchlists", err)
+			return
+		}
+		var out pb.Watchlists
+		for _, wl := range lists {
+			out.Watchlists = append(out.Watchlists, watchResult(wl))
+		}
+		writeProto(w, &out)
+
+	case http.MethodPost:
+		body, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, 1<<20))
+		if err != nil {
+			ErrorHandler(w, r, 400, "Can't read watchlist", err)
+			return
+		}
+		var in pb.Watchlist
+		if err := protojson.Unmarshal(body, &in); err != nil {
+			ErrorHandler(w, r, 400, "Bad watchlist", err)
+			return
+		}
+		wl, err := newWatchlist(&in)
+		if err != nil {
+			ErrorHandler(w, r, 400, "Bad watchlist", err)
+			return
+		}
+
+		if _, err := ensureTable(ctx, "watchlists", watchRow{}); err != nil {
+			ErrorHandler(w, r, 500, "Can't store watchlist", err)
+			return
+		}
+		query := client.Query(`INSERT INTO historical.watchlists
+		(ID, Name, ASNs, Prefixes, TAs, URL, Secret, Created)
+		VALUES (@id, @name, @asns, @prefixes, @tas, @url, @secret, @created)`)
+		query.Parameters = []bigquery.QueryParameter{
+			{Name: "id", Value: wl.ID},
+			{Name: "name", Value: wl.Name},
+			{Name: "asns", Value: append([]int64{}, wl.ASNs...)},
+			{Name: "prefixes", Value: append([]string{}, wl.Prefixes...)},
+			{Name: "tas", Value: append([]string{}, wl.TAs...)},
+			{Name: "url", Value: wl.URL},
+			{Name: "secret", Value: wl.Secret},
+			{Name: "created", Value: wl.Created},
+		}
+		if _, err := runQuery(ctx, query); err != nil {
+			ErrorHandler(w, r, 500, "Can't store watchlist", err)
+			return
+		}
+
+		out := watchResult(wl)
+		out.Secret = wl.Secret
+		writeProto(w, out)
+
+	case http.MethodDelete:
+		id := r.FormValue("id")
+		if id == "" {
+			ErrorHandler(w, r, 400, "Need an id", nil)
+			return
+		}
+		query := client.Query(`DELETE FROM historical.watchlists WHERE ID = @id`)
+		query.Parameters = []bigquery.QueryParameter{{Name: "id", Value: id}}
+		if _, err := runQuery(ctx, query); err != nil {
+			ErrorHandler(w, r, 500, "Can't delete watchlist", err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		ErrorHandler(w, r, 405, "GET, POST or DELETE", nil)
+	}
+}
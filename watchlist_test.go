@@ -0,0 +1,104 @@
+package main
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	pb "github.com/gidoBOSSftw5731/Historical-ROA/proto"
+)
+
+// startReceiver runs webhookreceiver with secret, so webhooks are checked
+// with its check rather than a copy of it
+func startReceiver(t *testing.T, secret string) string {
+	bin := filepath.Join(t.TempDir(), "webhookreceiver")
+	build := exec.Command("go", "build", "-o", bin, ".")
+	build.Dir = "webhookreceiver"
+	if out, err := build.CombinedOutput(); err != nil {
+		t.Fatalf("can't build webhookreceiver: %v\n%s", err, out)
+	}
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := l.Addr().String()
+	l.Close()
+
+	cmd := exec.Command(bin, "-listen", addr, "-secret", secret)
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		cmd.Process.Kill()
+		cmd.Wait()
+	})
+	for deadline := time.Now().Add(10 * time.Second); ; {
+		c, err := net.Dial("tcp", addr)
+		if err == nil {
+			c.Close()
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("webhookreceiver didn't start: %v", err)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	return "http://" + addr + "/"
+}
+
+func TestWebhookSignature(t *testing.T) {
+	t.Setenv("WEBHOOK_PRIVATE", "true")
+	url := startReceiver(t, "sekrit")
+	body := []byte(`{"watchlist":"1","added":[{"ASN":"AS64500"}]}`)
+
+	wl := &watchRow{ID: "1", URL: url, Secret: "sekrit"}
+	if err := postWebhook(context.Background(), wl, body); err != nil {
+		t.Errorf("receiver rejected a good webhook: %v", err)
+	}
+	wl.Secret = "wrong"
+	if err := postWebhook(context.Background(), wl, body); err == nil || !strings.Contains(err.Error(), "403") {
+		t.Errorf("receiver didn't reject a webhook with the wrong secret: %v", err)
+	}
+}
+
+func TestWebhookPrivate(t *testing.T) {
+	var hit atomic.Bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hit.Store(true)
+	}))
+	defer srv.Close()
+
+	t.Setenv("WEBHOOK_PRIVATE", "")
+	wl := &watchRow{ID: "1", URL: srv.URL, Secret: "sekrit"}
+	if err := postWebhook(context.Background(), wl, []byte("{}")); err == nil || hit.Load() {
+		t.Errorf("webhook to %v went through", srv.URL)
+	}
+	for _, u := range []string{"http://127.0.0.1/", "http://[::1]:8080/", "http://localhost/",
+		"http://10.1.2.3/", "http://169.254.169.254/", "http://[fe80::1%25eth0]/", "http://[::ffff:192.168.0.1]/",
+		"http://100.64.1.1/", "http://0.1.2.3/", "http://192.0.0.8/", "http://198.19.0.1/",
+		"http://[64:ff9b::a00:1]/", "http://[fd00::1]/"} {
+		if _, err := newWatchlist(&pb.Watchlist{Url: u}); err == nil {
+			t.Errorf("watchlist to %v was allowed", u)
+		}
+	}
+	if _, err := newWatchlist(&pb.Watchlist{Url: "https://8.8.8.8/hook"}); err != nil {
+		t.Errorf("watchlist to a public address wasn't allowed: %v", err)
+	}
+	if !webhookAllowed(netip.MustParseAddr("2606:4700:4700::1111")) {
+		t.Errorf("public IPv6 address wasn't allowed")
+	}
+
+	t.Setenv("WEBHOOK_PRIVATE", "true")
+	if err := postWebhook(context.Background(), wl, []byte("{}")); err != nil || !hit.Load() {
+		t.Errorf("webhook with WEBHOOK_PRIVATE didn't go through: %v", err)
+	}
+}
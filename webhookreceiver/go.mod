@@ -0,0 +1,5 @@
+module github.com/gidoBOSSftw5731/Historical-ROA/webhookreceiver
+
+go 1.13
+
+require github.com/gidoBOSSftw5731/log v0.0.0-20210527210830-1611311b4b64
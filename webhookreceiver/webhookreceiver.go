@@ -0,0 +1,72 @@
+// webhookreceiver is a local endpoint for testing watchlist webhooks. It
+// checks the signature and logs what it's sent. The server needs
+// WEBHOOK_PRIVATE=true to send to it.
+//
+//	go run . -listen 127.0.0.1:8082 -secret <the watchlist's secret>
+package main
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gidoBOSSftw5731/log"
+)
+
+// window is how far the timestamp may be from our clock
+const window = 5 * time.Minute
+
+func main() {
+	listen := flag.String("listen", "127.0.0.1:8082", "address to listen on")
+	secret := flag.String("secret", "", "the watchlist's secret")
+	flag.Parse()
+	log.SetCallDepth(2)
+
+	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), 400)
+			return
+		}
+		if err := check(r, []byte(*secret), body); err != nil {
+			log.Errorf("rejected webhook from %v: %v", r.RemoteAddr, err)
+			http.Error(w, err.Error(), 403)
+			return
+		}
+		log.Infof("webhook for watchlist %v:\n%s", r.Header.Get("X-Webhook-Id"), body)
+	})
+
+	log.Infof("listening on %v", *listen)
+	log.Fatalln(http.ListenAndServe(*listen, nil))
+}
+
+// check checks X-Webhook-Signature, which is
+// hex(HMAC-SHA256(secret, X-Webhook-Timestamp + "\n" + body))
+func check(r *http.Request, secret, body []byte) error {
+	ts := r.Header.Get("X-Webhook-Timestamp")
+	unix, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return fmt.Errorf("bad timestamp %q", ts)
+	}
+	if d := time.Since(time.Unix(unix, 0)); d > window || d < -window {
+		return fmt.Errorf("timestamp %v is too far off", ts)
+	}
+
+	sig, err := hex.DecodeString(r.Header.Get("X-Webhook-Signature"))
+	if err != nil {
+		return fmt.Errorf("bad signature")
+	}
+	mac := hmac.New(sha256.New, secret)
+	fmt.Fprintf(mac, "%s\n", ts)
+	mac.Write(body)
+	if !hmac.Equal(sig, mac.Sum(nil)) {
+		return fmt.Errorf("signature doesn't match")
+	}
+	return nil
+}